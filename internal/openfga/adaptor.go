@@ -32,6 +32,9 @@ func (s *openFGAServiceClientAdaptor) Write(ctx context.Context, in *openfgav1.W
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.Write(ctx, in, opts...)
 }
 
@@ -39,6 +42,9 @@ func (s *openFGAServiceClientAdaptor) Check(ctx context.Context, in *openfgav1.C
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.Check(ctx, in, opts...)
 }
 
@@ -46,6 +52,9 @@ func (s *openFGAServiceClientAdaptor) BatchCheck(ctx context.Context, in *openfg
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.BatchCheck(ctx, in, opts...)
 }
 
@@ -53,6 +62,9 @@ func (s *openFGAServiceClientAdaptor) Expand(ctx context.Context, in *openfgav1.
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.Expand(ctx, in, opts...)
 }
 
@@ -131,6 +143,9 @@ func (s *openFGAServiceClientAdaptor) StreamedListObjects(ctx context.Context, i
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.StreamedListObjects(ctx, in, opts...)
 }
 
@@ -138,6 +153,9 @@ func (s *openFGAServiceClientAdaptor) ListObjects(ctx context.Context, in *openf
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.ListObjects(ctx, in, opts...)
 }
 
@@ -145,5 +163,8 @@ func (s *openFGAServiceClientAdaptor) ListUsers(ctx context.Context, in *openfga
 	if in != nil && in.StoreId == "" {
 		in.StoreId = s.storeId
 	}
+	if in != nil && in.AuthorizationModelId == "" {
+		in.AuthorizationModelId = s.modelId
+	}
 	return s.client.ListUsers(ctx, in, opts...)
 }
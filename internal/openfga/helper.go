@@ -12,10 +12,10 @@ import (
 )
 
 // CreateOpenFGAClient creates and validates an OpenFGA service client
-func CreateOpenFGAClient(cc grpc.ClientConnInterface, storeId, modelId string) (openfgav1.OpenFGAServiceClient, error) {
+func CreateOpenFGAClient(ctx context.Context, cc grpc.ClientConnInterface, storeId, modelId string) (openfgav1.OpenFGAServiceClient, error) {
 	serviceClient := openfgav1.NewOpenFGAServiceClient(cc)
 
-	storeId, err := checkStore(serviceClient, storeId)
+	storeId, err := checkStore(ctx, serviceClient, storeId)
 	if err != nil {
 		return nil, err
 	}
@@ -23,18 +23,28 @@ func CreateOpenFGAClient(cc grpc.ClientConnInterface, storeId, modelId string) (
 	log.Infof("OpenFGA Store: %s", storeId)
 	fgaClient := NewOpenFGAServiceClient(cc, storeId, modelId)
 
-	if err := validateModel(fgaClient, storeId, modelId); err != nil {
+	if err := validateModel(ctx, fgaClient, storeId, modelId); err != nil {
 		return nil, err
 	}
 
 	return fgaClient, nil
 }
 
-func checkStore(client openfgav1.OpenFGAServiceClient, storeId string) (string, error) {
+// ValidateStore checks that the store exists and is not deleted, and that it has the model,
+// or any model when modelId is empty.
+func ValidateStore(ctx context.Context, client openfgav1.OpenFGAServiceClient, storeId, modelId string) error {
+	storeId, err := checkStore(ctx, client, storeId)
+	if err != nil {
+		return err
+	}
+	return validateModel(ctx, client, storeId, modelId)
+}
+
+func checkStore(ctx context.Context, client openfgav1.OpenFGAServiceClient, storeId string) (string, error) {
 	if storeId == "" {
 		log.Infof("Finding latest OpenFGA Store...")
 
-		store, err := findLatestStore(client)
+		store, err := findLatestStore(ctx, client)
 		if err != nil {
 			return "", err
 		}
@@ -46,7 +56,7 @@ func checkStore(client openfgav1.OpenFGAServiceClient, storeId string) (string,
 		return store.GetId(), nil
 	}
 
-	store, err := client.GetStore(context.Background(), &openfgav1.GetStoreRequest{
+	store, err := client.GetStore(ctx, &openfgav1.GetStoreRequest{
 		StoreId: storeId,
 	})
 	if err != nil {
@@ -62,8 +72,8 @@ func checkStore(client openfgav1.OpenFGAServiceClient, storeId string) (string,
 }
 
 // findLatestStore 가장 최근에 생성된 Store를 반환
-func findLatestStore(client openfgav1.OpenFGAServiceClient) (*openfgav1.Store, error) {
-	stores, err := findActiveStores(client)
+func findLatestStore(ctx context.Context, client openfgav1.OpenFGAServiceClient) (*openfgav1.Store, error) {
+	stores, err := findActiveStores(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -82,14 +92,14 @@ func findLatestStore(client openfgav1.OpenFGAServiceClient) (*openfgav1.Store, e
 }
 
 // listAllStores 모든 Store를 조회
-func listAllStores(client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store, error) {
+func listAllStores(ctx context.Context, client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store, error) {
 	var (
 		stores            []*openfgav1.Store
 		continuationToken string
 	)
 
 	for {
-		resp, err := client.ListStores(context.Background(), &openfgav1.ListStoresRequest{
+		resp, err := client.ListStores(ctx, &openfgav1.ListStoresRequest{
 			PageSize:          wrapperspb.Int32(100),
 			ContinuationToken: continuationToken,
 		})
@@ -109,9 +119,42 @@ func listAllStores(client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store, e
 	return stores, nil
 }
 
+// FindStoreByName returns the only active store named name.
+func FindStoreByName(ctx context.Context, client openfgav1.OpenFGAServiceClient, name string) (*openfgav1.Store, error) {
+	stores, err := findActiveStores(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*openfgav1.Store
+	for _, store := range stores {
+		if store.GetName() == name {
+			matches = append(matches, store)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no active OpenFGA store named %q", name)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, 0, len(matches))
+		for _, store := range matches {
+			ids = append(ids, store.GetId())
+		}
+		return nil, fmt.Errorf("%d active OpenFGA stores are named %q (%v), set store_id instead", len(matches), name, ids)
+	}
+}
+
+// FindActiveStores returns every store that has not been deleted.
+func FindActiveStores(ctx context.Context, client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store, error) {
+	return findActiveStores(ctx, client)
+}
+
 // findActiveStores 활성화된 Store를 조회
-func findActiveStores(client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store, error) {
-	stores, err := listAllStores(client)
+func findActiveStores(ctx context.Context, client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store, error) {
+	stores, err := listAllStores(ctx, client)
 	if err != nil {
 		return nil, err
 	}
@@ -130,23 +173,30 @@ func findActiveStores(client openfgav1.OpenFGAServiceClient) ([]*openfgav1.Store
 
 // ValidateConnection validates the client connection
 func ValidateConnection(ctx context.Context, conn grpc.ClientConnInterface) error {
+	_, err := CheckHealth(ctx, conn)
+	return err
+}
+
+// CheckHealth returns the gRPC health status of the server, with an error unless it is serving.
+func CheckHealth(ctx context.Context, conn grpc.ClientConnInterface) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
 	// 헬스 체크
 	healthClient := grpc_health_v1.NewHealthClient(conn)
 	resp, err := healthClient.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
 	if err != nil {
-		return err
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN, err
 	}
 
 	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
-		return fmt.Errorf("OpenFGA server is not serving: %s", resp.Status)
+		return resp.Status, fmt.Errorf("OpenFGA server is not serving: %s", resp.Status)
 	}
-	return nil
+	return resp.Status, nil
 }
 
-func validateModel(client openfgav1.OpenFGAServiceClient, storeId, modelId string) error {
+func validateModel(ctx context.Context, client openfgav1.OpenFGAServiceClient, storeId, modelId string) error {
 	if modelId == "" {
-		res, err := client.ReadAuthorizationModels(context.Background(), &openfgav1.ReadAuthorizationModelsRequest{
-			StoreId: storeId,
+		res, err := client.ReadAuthorizationModels(ctx, &openfgav1.ReadAuthorizationModelsRequest{
+			StoreId:  storeId,
+			PageSize: wrapperspb.Int32(1),
 		})
 		if err != nil {
 			return err
@@ -156,7 +206,7 @@ func validateModel(client openfgav1.OpenFGAServiceClient, storeId, modelId strin
 			return fmt.Errorf("no OpenFGA AuthorizationModels found in store %s", storeId)
 		}
 	} else {
-		_, err := client.ReadAuthorizationModel(context.Background(), &openfgav1.ReadAuthorizationModelRequest{
+		_, err := client.ReadAuthorizationModel(ctx, &openfgav1.ReadAuthorizationModelRequest{
 			StoreId: storeId,
 			Id:      modelId,
 		})
@@ -0,0 +1,169 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	authModeNone     = "none"
+	authModeApiToken = "api_token"
+	authModeOIDC     = "client_credentials"
+)
+
+// tokenRefreshMargin is how long before expiry an OIDC token is replaced, so no call carries a token about to expire.
+var tokenRefreshMargin = time.Minute
+
+// tokenRequestTimeout bounds a token request, so an unresponsive issuer fails the RPC instead of hanging it.
+var tokenRequestTimeout = 10 * time.Second
+
+// newPerRPCCredentials returns the bearer credentials for the configured auth mode, or nil without authentication.
+// Tokens are refused over plaintext unless allow_insecure_api_token is set.
+func newPerRPCCredentials(cfg Config) (credentials.PerRPCCredentials, string, error) {
+	token := stringValue(cfg.ApiToken)
+	useOIDC := stringValue(cfg.ApiTokenIssuer) != "" || stringValue(cfg.ClientId) != "" || stringValue(cfg.ClientSecret) != ""
+
+	var (
+		source oauth2.TokenSource
+		mode   string
+	)
+	switch {
+	case token != "" && useOIDC:
+		return nil, "", fmt.Errorf("api_token cannot be combined with api_token_issuer, client_id and client_secret")
+	case token != "":
+		source = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
+		mode = authModeApiToken
+	case useOIDC:
+		var err error
+		if source, err = newClientCredentialsTokenSource(cfg); err != nil {
+			return nil, "", err
+		}
+		mode = authModeOIDC
+	default:
+		return nil, authModeNone, nil
+	}
+
+	allowInsecure := cfg.AllowInsecureApiToken != nil && *cfg.AllowInsecureApiToken
+	if !useTLS(cfg) && !allowInsecure {
+		return nil, "", fmt.Errorf("%s would be sent in plaintext: set use_tls = true, or allow_insecure_api_token = true for trusted networks", mode)
+	}
+	return &bearerCredentials{source: source, requireTLS: !allowInsecure}, mode, nil
+}
+
+// newClientCredentialsTokenSource builds an OAuth2 client-credentials token source that caches the token
+// and fetches a new one tokenRefreshMargin before it expires.
+func newClientCredentialsTokenSource(cfg Config) (oauth2.TokenSource, error) {
+	issuer := stringValue(cfg.ApiTokenIssuer)
+	clientID := stringValue(cfg.ClientId)
+	clientSecret := stringValue(cfg.ClientSecret)
+	if issuer == "" || clientID == "" || clientSecret == "" {
+		return nil, fmt.Errorf("api_token_issuer, client_id and client_secret are all required for client credentials authentication")
+	}
+
+	tokenURL, err := buildTokenURL(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	ccConfig := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     tokenURL,
+		Scopes:       cfg.ApiScopes,
+	}
+	if audience := stringValue(cfg.ApiAudience); audience != "" {
+		ccConfig.EndpointParams = url.Values{"audience": {audience}}
+	}
+
+	// 토큰 소스는 Client 수명 동안 공유되므로 쿼리 context 가 아닌 Background 를 사용한다.
+	// The timeout therefore comes from the HTTP client the token source picks up from the context.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: tokenRequestTimeout})
+	return oauth2.ReuseTokenSourceWithExpiry(nil, ccConfig.TokenSource(ctx), tokenRefreshMargin), nil
+}
+
+// buildTokenURL accepts either a full token endpoint or a bare issuer, which gets https:// and /oauth/token
+// like the OpenFGA SDKs do.
+func buildTokenURL(issuer string) (string, error) {
+	if !strings.Contains(issuer, "://") {
+		issuer = "https://" + issuer
+	}
+
+	u, err := url.Parse(issuer)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid api_token_issuer %q", issuer)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/oauth/token"
+	}
+	return u.String(), nil
+}
+
+// bearerCredentials attaches "authorization: Bearer <token>" metadata to every call.
+type bearerCredentials struct {
+	source     oauth2.TokenSource
+	requireTLS bool
+}
+
+func (b *bearerCredentials) GetRequestMetadata(_ context.Context, _ ...string) (map[string]string, error) {
+	token, err := b.source.Token()
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "failed to obtain access token: %v", err)
+	}
+	return map[string]string{"authorization": "Bearer " + token.AccessToken}, nil
+}
+
+func (b *bearerCredentials) RequireTransportSecurity() bool {
+	return b.requireTLS
+}
+
+// authError turns an Unauthenticated status into an error naming the connection and its auth settings.
+// The status is wrapped, so status.Code still reports codes.Unauthenticated.
+func (c *Client) authError(err error) error {
+	if status.Code(err) != codes.Unauthenticated {
+		return err
+	}
+
+	var hint string
+	switch c.authMode {
+	case authModeApiToken:
+		hint = "check that api_token matches a preshared key configured on the OpenFGA server"
+	case authModeOIDC:
+		hint = "check api_token_issuer, client_id, client_secret and api_audience against the OpenFGA OIDC settings"
+	default:
+		hint = "the server requires authentication, set api_token or client credentials in the connection config"
+	}
+	return fmt.Errorf("connection %q: OpenFGA rejected the request as unauthenticated, %s: %w", c.connectionName, hint, err)
+}
+
+func (c *Client) unaryAuthErrorInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return c.authError(invoker(ctx, method, req, reply, cc, opts...))
+}
+
+func (c *Client) streamAuthErrorInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, c.authError(err)
+	}
+	return &authErrorStream{ClientStream: stream, client: c}, nil
+}
+
+// authErrorStream applies authError to server-streaming responses, whose status arrives on RecvMsg.
+type authErrorStream struct {
+	grpc.ClientStream
+	client *Client
+}
+
+func (s *authErrorStream) RecvMsg(m any) error {
+	return s.client.authError(s.ClientStream.RecvMsg(m))
+}
@@ -0,0 +1,245 @@
+package openfga
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// requireBearer is a server interceptor accepting only "authorization: Bearer <token>".
+func requireBearer(token string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if got := md.Get("authorization"); len(got) != 1 || got[0] != "Bearer "+token {
+			return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+		}
+		return handler(ctx, req)
+	}
+}
+
+func TestNewClient_ApiToken(t *testing.T) {
+	addr := startTestServer(t, &fakeOpenFGAServer{}, grpc.UnaryInterceptor(requireBearer("secret")))
+
+	t.Run("valid token", func(t *testing.T) {
+		err := checkWithConfig(t, Config{
+			Endpoint:              addr,
+			ApiToken:              ptr("secret"),
+			AllowInsecureApiToken: ptr(true),
+		})
+		if err != nil {
+			t.Fatalf("Check with a valid token failed: %v", err)
+		}
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		client, err := NewClient(context.Background(), Config{
+			Endpoint:              addr,
+			ApiToken:              ptr("wrong"),
+			AllowInsecureApiToken: ptr(true),
+		})
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		defer client.Close()
+		client.connectionName = "openfga_prod"
+
+		_, err = client.Check(context.Background(), testCheckRequest())
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("Expected Unauthenticated, got %v", err)
+		}
+		if !strings.Contains(err.Error(), `connection "openfga_prod"`) || !strings.Contains(err.Error(), "api_token") {
+			t.Fatalf("Expected an actionable error naming the connection, got %v", err)
+		}
+	})
+
+	t.Run("plaintext is refused by default", func(t *testing.T) {
+		_, err := NewClient(context.Background(), Config{
+			Endpoint: addr,
+			ApiToken: ptr("secret"),
+		})
+		if err == nil || !strings.Contains(err.Error(), "plaintext") {
+			t.Fatalf("Expected plaintext token to be refused, got %v", err)
+		}
+	})
+}
+
+func TestNewClient_ApiTokenOverTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	tlsAddr := startTLSServer(t, pki, false, grpc.UnaryInterceptor(requireBearer("secret")))
+
+	err := checkWithConfig(t, Config{
+		Endpoint:      tlsAddr,
+		UseTLS:        ptr(true),
+		CACertPath:    &pki.caPath,
+		TLSServerName: ptr("openfga.test"),
+		ApiToken:      ptr("secret"),
+	})
+	if err != nil {
+		t.Fatalf("Check with a token over TLS failed: %v", err)
+	}
+}
+
+// testTokenServer is a stand-in OAuth2 token endpoint issuing numbered tokens.
+type testTokenServer struct {
+	*httptest.Server
+	expiresIn int
+	requests  atomic.Int32
+}
+
+func newTestTokenServer(t *testing.T, expiresIn int) *testTokenServer {
+	t.Helper()
+
+	ts := &testTokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/oauth/token" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok {
+			clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" || clientID != "steampipe" || clientSecret != "s3cret" ||
+			r.PostForm.Get("audience") != "https://openfga.test/" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+
+		n := ts.requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   ts.expiresIn,
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// requireIssuedToken accepts any bearer token issued by testTokenServer.
+func requireIssuedToken(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if got := md.Get("authorization"); len(got) != 1 || !strings.HasPrefix(got[0], "Bearer token-") {
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	return handler(ctx, req)
+}
+
+func TestNewClient_ClientCredentials(t *testing.T) {
+	addr := startTestServer(t, &fakeOpenFGAServer{}, grpc.UnaryInterceptor(requireIssuedToken))
+
+	newOIDCClient := func(t *testing.T, issuer, secret string) *Client {
+		t.Helper()
+		client, err := NewClient(context.Background(), Config{
+			Endpoint:              addr,
+			AllowInsecureApiToken: ptr(true),
+			ApiTokenIssuer:        &issuer,
+			ClientId:              ptr("steampipe"),
+			ClientSecret:          &secret,
+			ApiAudience:           ptr("https://openfga.test/"),
+		})
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+
+	t.Run("token is cached across calls", func(t *testing.T) {
+		issuer := newTestTokenServer(t, 3600)
+		client := newOIDCClient(t, issuer.URL, "s3cret")
+
+		for i := 0; i < 3; i++ {
+			if _, err := client.Check(context.Background(), testCheckRequest()); err != nil {
+				t.Fatalf("Check %d failed: %v", i, err)
+			}
+		}
+		if got := issuer.requests.Load(); got != 1 {
+			t.Fatalf("Expected 1 token request, got %d", got)
+		}
+	})
+
+	t.Run("token is refreshed before expiry", func(t *testing.T) {
+		// expires_in below tokenRefreshMargin forces a new token on every call
+		issuer := newTestTokenServer(t, int(tokenRefreshMargin.Seconds())/2)
+		client := newOIDCClient(t, issuer.URL+"/oauth/token", "s3cret")
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.Check(context.Background(), testCheckRequest()); err != nil {
+				t.Fatalf("Check %d failed: %v", i, err)
+			}
+		}
+		if got := issuer.requests.Load(); got != 2 {
+			t.Fatalf("Expected 2 token requests, got %d", got)
+		}
+	})
+
+	t.Run("rejected client secret", func(t *testing.T) {
+		issuer := newTestTokenServer(t, 3600)
+		client := newOIDCClient(t, issuer.URL, "wrong")
+
+		_, err := client.Check(context.Background(), testCheckRequest())
+		if status.Code(err) != codes.Unauthenticated || !strings.Contains(err.Error(), "client_secret") {
+			t.Fatalf("Expected an actionable Unauthenticated error, got %v", err)
+		}
+	})
+
+	t.Run("unresponsive issuer times out", func(t *testing.T) {
+		saved := tokenRequestTimeout
+		tokenRequestTimeout = 100 * time.Millisecond
+		t.Cleanup(func() { tokenRequestTimeout = saved })
+
+		release := make(chan struct{})
+		issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(issuer.Close)
+		t.Cleanup(func() { close(release) })
+		client := newOIDCClient(t, issuer.URL, "s3cret")
+
+		start := time.Now()
+		if _, err := client.Check(context.Background(), testCheckRequest()); err == nil {
+			t.Fatal("Expected Check to fail while the issuer does not answer")
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Fatalf("Check took %s, the token request should have timed out", elapsed)
+		}
+	})
+}
+
+func TestBuildTokenURL(t *testing.T) {
+	tests := []struct {
+		issuer string
+		want   string
+	}{
+		{"issuer.example.com", "https://issuer.example.com/oauth/token"},
+		{"https://issuer.example.com/", "https://issuer.example.com/oauth/token"},
+		{"http://localhost:9000/realms/fga/protocol/openid-connect/token", "http://localhost:9000/realms/fga/protocol/openid-connect/token"},
+	}
+
+	for _, tt := range tests {
+		got, err := buildTokenURL(tt.issuer)
+		if err != nil || got != tt.want {
+			t.Errorf("buildTokenURL(%q) = (%q, %v), want %q", tt.issuer, got, err, tt.want)
+		}
+	}
+}
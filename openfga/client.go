@@ -5,17 +5,96 @@ import (
 	"fmt"
 	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"golang.org/x/sync/singleflight"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"slices"
 	"sync"
 	"time"
 )
 
+// Client is shared by every query of a connection. getClient hands out copies scoped to one store,
+// which share the connection and the caches below.
 type Client struct {
 	openfgav1.OpenFGAServiceClient
-	conn    *grpc.ClientConn
+	conn *grpc.ClientConn
+	// storeID is the store requests go to: the store of the current matrix item, else the configured store_id
 	storeID string
+	// storeIDs is the configured store_ids; ["*"] means every active store
+	storeIDs []string
+	// storeName is the configured store_name, resolved to storeID when the client is first created
+	storeName string
+	modelID   string
+	// consistency is the default consistency preference, overridable per query with the consistency qual
+	consistency openfgav1.ConsistencyPreference
+	// validateOnConnect runs validate before the client is cached
+	validateOnConnect bool
+
+	// connectionName and authMode are only used to make authentication errors actionable
+	connectionName string
+	authMode       string
+
+	cache *resolveCache
+}
+
+// resolveCache holds values resolved from OpenFGA that are reused across queries for a short while.
+type resolveCache struct {
+	// mu guards the fields below and is never held during an RPC; inflight lets concurrent misses share one
+	mu       sync.Mutex
+	inflight singleflight.Group
+	// latest model per store, used when no model is pinned; refreshed after latestModelTTL
+	latestModels map[string]cachedModel
+	// active stores for store_ids = ["*"]; refreshed after activeStoresTTL
+	activeStores   []string
+	activeStoresAt time.Time
+}
+
+type cachedModel struct {
+	id string
+	at time.Time
+}
+
+// latestModelTTL bounds how long a resolved "latest" model is reused before asking OpenFGA again.
+const latestModelTTL = 30 * time.Second
+
+// resolveModelID returns modelID when it is set, otherwise the latest authorization model of the store.
+func (c *Client) resolveModelID(ctx context.Context, modelID string) (string, error) {
+	if modelID != "" {
+		return modelID, nil
+	}
+
+	c.cache.mu.Lock()
+	cached, ok := c.cache.latestModels[c.storeID]
+	c.cache.mu.Unlock()
+	if ok && time.Since(cached.at) < latestModelTTL {
+		return cached.id, nil
+	}
+
+	id, err, _ := c.cache.inflight.Do("model:"+c.storeID, func() (any, error) {
+		// ReadAuthorizationModels 는 최신 모델부터 반환한다.
+		res, err := c.ReadAuthorizationModels(ctx, &openfgav1.ReadAuthorizationModelsRequest{
+			StoreId:  c.storeID,
+			PageSize: wrapperspb.Int32(1),
+		})
+		if err != nil {
+			return "", fmt.Errorf("ReadAuthorizationModels: %w", err)
+		}
+
+		models := res.GetAuthorizationModels()
+		if len(models) == 0 {
+			return "", fmt.Errorf("no authorization model found in store %s", c.storeID)
+		}
+
+		c.cache.mu.Lock()
+		c.cache.latestModels[c.storeID] = cachedModel{id: models[0].GetId(), at: time.Now()}
+		c.cache.mu.Unlock()
+		return models[0].GetId(), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id.(string), nil
 }
 
 func (c *Client) Close() error {
@@ -38,7 +117,34 @@ func clearClientCache() {
 	})
 }
 
+// getClient returns the connection's client, scoped to the store of the current matrix item.
+// It fails when no store resolves, which is how errors of storeMatrix reach the query.
 func getClient(ctx context.Context, d *plugin.QueryData) (*Client, error) {
+	client, err := getConnectionClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	if storeID := d.EqualsQuals[storeIDCol].GetStringValue(); storeID != "" {
+		// store_ids 는 허용 목록이다
+		if len(client.storeIDs) > 0 && !slices.Equal(client.storeIDs, []string{allStores}) && !slices.Contains(client.storeIDs, storeID) {
+			return nil, fmt.Errorf("connection %q: store %s is not in store_ids", client.connectionName, storeID)
+		}
+		return client.withStore(storeID), nil
+	}
+	if client.storeID == "" {
+		storeIDs, err := client.configuredStores(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(storeIDs) == 0 && slices.Equal(client.storeIDs, []string{allStores}) {
+			return nil, fmt.Errorf("connection %q: store_ids = [\"*\"] found no active store on the server", client.connectionName)
+		}
+		return nil, fmt.Errorf("no store to query, qualify %s", storeIDCol)
+	}
+	return client, nil
+}
+
+func getConnectionClient(ctx context.Context, d *plugin.QueryData) (*Client, error) {
 	connName := d.Connection.Name
 	if v, ok := clientCache.Load(connName); ok {
 		return v.(*Client), nil
@@ -48,7 +154,19 @@ func getClient(ctx context.Context, d *plugin.QueryData) (*Client, error) {
 
 	client, err := NewClient(ctx, cfg)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("connection %q: %w", connName, err)
+	}
+	client.connectionName = connName
+
+	if err := client.resolveStoreName(ctx); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("connection %q: %w", connName, err)
+	}
+	if client.validateOnConnect {
+		if err := client.validate(ctx); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("connection %q: %w", connName, err)
+		}
 	}
 
 	// LoadOrStore로 race 방지
@@ -68,11 +186,29 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 		return nil, fmt.Errorf("endpoint is required in connection config")
 	}
 
-	// Extract storeID
-	var storeID string
+	// Extract storeID and the pinned authorization model
+	var storeID, storeName, modelID string
 	if cfg.StoreId != nil {
 		storeID = *cfg.StoreId
 	}
+	if cfg.StoreName != nil {
+		storeName = *cfg.StoreName
+	}
+	if cfg.AuthorizationModelId != nil {
+		modelID = *cfg.AuthorizationModelId
+	}
+	if err := validateStoreConfig(storeID, storeName, cfg.StoreIds); err != nil {
+		return nil, err
+	}
+
+	// 기존 동작과 같도록 기본값은 higher_consistency
+	consistency := openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY
+	if cfg.Consistency != nil {
+		var err error
+		if consistency, err = parseConsistency(*cfg.Consistency); err != nil {
+			return nil, err
+		}
+	}
 
 	// Configure dial options following gRPC best practices
 	// https://github.com/grpc/grpc-go/blob/master/Documentation/anti-patterns.md
@@ -87,21 +223,43 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 		}),
 	}
 
+	retry, err := newRetryPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	// Configure TLS/credentials
-	useTLS := false
-	if cfg.UseTLS != nil {
-		useTLS = *cfg.UseTLS
+	transportCreds, err := newTransportCredentials(cfg)
+	if err != nil {
+		return nil, err
 	}
+	dialOpts = append(dialOpts, grpc.WithTransportCredentials(transportCreds))
 
-	if useTLS {
-		// TLS configuration will be added here in the future
-		// For now, use insecure credentials
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	} else {
-		// Use insecure credentials for non-TLS connections
-		dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	client := &Client{
+		storeID:           storeID,
+		storeIDs:          cfg.StoreIds,
+		storeName:         storeName,
+		modelID:           modelID,
+		consistency:       consistency,
+		validateOnConnect: cfg.ValidateConnection != nil && *cfg.ValidateConnection,
+		cache:             &resolveCache{latestModels: map[string]cachedModel{}},
 	}
 
+	// Configure per-RPC authentication
+	creds, authMode, err := newPerRPCCredentials(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if creds != nil {
+		dialOpts = append(dialOpts, grpc.WithPerRPCCredentials(creds))
+	}
+	client.authMode = authMode
+	dialOpts = append(dialOpts,
+		// retry 가 바깥쪽이라 시도마다 auth 인터셉터를 거친다
+		grpc.WithChainUnaryInterceptor(retry.unaryInterceptor, client.unaryAuthErrorInterceptor),
+		grpc.WithChainStreamInterceptor(retry.streamInterceptor, client.streamAuthErrorInterceptor),
+	)
+
 	// Use grpc.NewClient (recommended since v1.63.0)
 	// This performs NO I/O during construction - connections are established lazily
 	// Errors should be handled at RPC call time, not at dial time
@@ -111,12 +269,7 @@ func NewClient(ctx context.Context, cfg Config) (*Client, error) {
 	}
 
 	// Create OpenFGA service client
-	fgaServiceClient := openfgav1.NewOpenFGAServiceClient(conn)
-
-	client := &Client{
-		OpenFGAServiceClient: fgaServiceClient,
-		conn:                 conn,
-		storeID:              storeID,
-	}
+	client.OpenFGAServiceClient = openfgav1.NewOpenFGAServiceClient(conn)
+	client.conn = conn
 	return client, nil
 }
@@ -0,0 +1,78 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+)
+
+// modelListServer answers ReadAuthorizationModels with a new latest model on every call.
+type modelListServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	calls atomic.Int32
+}
+
+func (s *modelListServer) ReadAuthorizationModels(_ context.Context, _ *openfgav1.ReadAuthorizationModelsRequest) (*openfgav1.ReadAuthorizationModelsResponse, error) {
+	n := s.calls.Add(1)
+	return &openfgav1.ReadAuthorizationModelsResponse{
+		AuthorizationModels: []*openfgav1.AuthorizationModel{{Id: fmt.Sprintf("m%d", n)}},
+	}, nil
+}
+
+func TestResolveModelID(t *testing.T) {
+	srv := &modelListServer{}
+	addr := startTestServer(t, srv)
+
+	client, err := NewClient(context.Background(), Config{Endpoint: addr, StoreId: ptr("a")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	resolve := func(want string) {
+		t.Helper()
+		got, err := client.resolveModelID(context.Background(), "")
+		if err != nil || got != want {
+			t.Fatalf("resolveModelID = (%q, %v), want %q", got, err, want)
+		}
+	}
+
+	t.Run("pinned model", func(t *testing.T) {
+		got, err := client.resolveModelID(context.Background(), "pinned")
+		if err != nil || got != "pinned" || srv.calls.Load() != 0 {
+			t.Fatalf("resolveModelID(pinned) = (%q, %v) after %d calls", got, err, srv.calls.Load())
+		}
+	})
+
+	t.Run("reused within the TTL", func(t *testing.T) {
+		resolve("m1")
+		resolve("m1")
+		if n := srv.calls.Load(); n != 1 {
+			t.Fatalf("Expected 1 ReadAuthorizationModels call, got %d", n)
+		}
+	})
+
+	t.Run("refreshed after the TTL", func(t *testing.T) {
+		client.cache.mu.Lock()
+		client.cache.latestModels["a"] = cachedModel{id: "m1", at: time.Now().Add(-latestModelTTL)}
+		client.cache.mu.Unlock()
+
+		resolve("m2")
+		resolve("m2")
+		if n := srv.calls.Load(); n != 2 {
+			t.Fatalf("Expected 2 ReadAuthorizationModels calls, got %d", n)
+		}
+	})
+
+	t.Run("cached per store", func(t *testing.T) {
+		got, err := client.withStore("b").resolveModelID(context.Background(), "")
+		if err != nil || got != "m3" {
+			t.Fatalf("resolveModelID for store b = (%q, %v), want m3", got, err)
+		}
+		resolve("m2")
+	})
+}
@@ -75,7 +75,7 @@ package openfga
 //	}
 //
 //	// Create OpenFGA service client with adaptor
-//	fgaClient, err := openfgainternal.CreateOpenFGAClient(conn, storeId, modelId)
+//	fgaClient, err := openfgainternal.CreateOpenFGAClient(ctx, conn, storeId, modelId)
 //	if err != nil {
 //		conn.Close()
 //		return nil, fmt.Errorf("failed to create OpenFGA client: %w", err)
@@ -10,10 +10,32 @@ type Config struct {
 	UseTLS             *bool   `hcl:"use_tls" env:"OPENFGA_USE-TLS"`                           // 기본은 false (내부망)
 	CACertPath         *string `hcl:"ca_cert_path" env:"OPENFGA_CA-CERT-PATH"`                 // TLS 시 CA 경로
 	InsecureSkipVerify *bool   `hcl:"insecure_skip_verify" env:"OPENFGA_INSECURE-SKIP-VERIFY"` // 필요시만
+	ClientCertPath     *string `hcl:"client_cert_path" env:"OPENFGA_CLIENT-CERT-PATH"`         // mTLS 클라이언트 인증서
+	ClientKeyPath      *string `hcl:"client_key_path" env:"OPENFGA_CLIENT-KEY-PATH"`           // mTLS 클라이언트 키
+	TLSServerName      *string `hcl:"tls_server_name" env:"OPENFGA_TLS-SERVER-NAME"`           // SNI / 인증서 이름 override
 
-	ApiToken             *string `hcl:"api_token" env:"OPENFGA_API-TOKEN"`
-	StoreId              *string `hcl:"store_id" env:"OPENFGA_STORE-ID"`
-	AuthorizationModelId *string `hcl:"authorization_model_id" env:"OPENFGA_AUTHORIZATION-MODEL-ID"`
+	ApiToken              *string `hcl:"api_token" env:"OPENFGA_API-TOKEN"`
+	AllowInsecureApiToken *bool   `hcl:"allow_insecure_api_token" env:"OPENFGA_ALLOW-INSECURE-API-TOKEN"` // 평문 연결에서 토큰 전송 허용
+
+	// OIDC client credentials, api_token 대신 사용
+	ApiTokenIssuer       *string  `hcl:"api_token_issuer" env:"OPENFGA_API-TOKEN-ISSUER"`
+	ClientId             *string  `hcl:"client_id" env:"OPENFGA_CLIENT-ID"`
+	ClientSecret         *string  `hcl:"client_secret" env:"OPENFGA_CLIENT-SECRET"`
+	ApiAudience          *string  `hcl:"api_audience" env:"OPENFGA_API-AUDIENCE"`
+	ApiScopes            []string `hcl:"api_scopes,optional" env:"OPENFGA_API-SCOPES"`
+	StoreId              *string  `hcl:"store_id" env:"OPENFGA_STORE-ID"`
+	StoreIds             []string `hcl:"store_ids,optional" env:"OPENFGA_STORE-IDS"` // 여러 store 조회, ["*"] 이면 삭제되지 않은 모든 store
+	StoreName            *string  `hcl:"store_name" env:"OPENFGA_STORE-NAME"`        // 처음 사용할 때 ListStores 로 store_id 를 찾음
+	AuthorizationModelId *string  `hcl:"authorization_model_id" env:"OPENFGA_AUTHORIZATION-MODEL-ID"`
+	Consistency          *string  `hcl:"consistency" env:"OPENFGA_CONSISTENCY"`                 // minimize_latency | higher_consistency(기본) | unspecified
+	ValidateConnection   *bool    `hcl:"validate_connection" env:"OPENFGA_VALIDATE-CONNECTION"` // 처음 사용할 때 health, store, model 확인
+
+	// 읽기 RPC 재시도와 RPC 별 timeout, 기간은 "500ms", "30s" 형식
+	MaxRetries      *int              `hcl:"max_retries" env:"OPENFGA_MAX-RETRIES"`
+	RetryBackoff    *string           `hcl:"retry_backoff" env:"OPENFGA_RETRY-BACKOFF"`         // 첫 재시도 대기, 이후 두 배씩 (jitter 적용)
+	RetryMaxBackoff *string           `hcl:"retry_max_backoff" env:"OPENFGA_RETRY-MAX-BACKOFF"` // 재시도 대기 상한
+	RequestTimeout  *string           `hcl:"request_timeout" env:"OPENFGA_REQUEST-TIMEOUT"`     // 시도마다 적용 (스트림은 첫 메시지까지), "0s" 이면 없음
+	MethodTimeouts  map[string]string `hcl:"method_timeouts,optional"`                          // RPC 이름별 request_timeout, 예: { StreamedListObjects = "60s" }
 }
 
 func ConfigInstance() any {
@@ -33,24 +55,82 @@ var ConfigSchema = map[string]*schema.Attribute{
 	"insecure_skip_verify": {
 		Type: schema.TypeBool,
 	},
+	"client_cert_path": {
+		Type: schema.TypeString,
+	},
+	"client_key_path": {
+		Type: schema.TypeString,
+	},
+	"tls_server_name": {
+		Type: schema.TypeString,
+	},
 	"api_token": {
 		Type: schema.TypeString,
 	},
+	"allow_insecure_api_token": {
+		Type: schema.TypeBool,
+	},
+	"api_token_issuer": {
+		Type: schema.TypeString,
+	},
+	"client_id": {
+		Type: schema.TypeString,
+	},
+	"client_secret": {
+		Type: schema.TypeString,
+	},
+	"api_audience": {
+		Type: schema.TypeString,
+	},
+	"api_scopes": {
+		Type: schema.TypeList,
+		Elem: &schema.Attribute{Type: schema.TypeString},
+	},
+	"store_name": {
+		Type: schema.TypeString,
+	},
 	"store_id": {
 		Type: schema.TypeString,
 	},
+	"store_ids": {
+		Type: schema.TypeList,
+		Elem: &schema.Attribute{Type: schema.TypeString},
+	},
 	"authorization_model_id": {
 		Type: schema.TypeString,
 	},
+	"consistency": {
+		Type: schema.TypeString,
+	},
+	"validate_connection": {
+		Type: schema.TypeBool,
+	},
+	"max_retries": {
+		Type: schema.TypeInt,
+	},
+	"retry_backoff": {
+		Type: schema.TypeString,
+	},
+	"retry_max_backoff": {
+		Type: schema.TypeString,
+	},
+	"request_timeout": {
+		Type: schema.TypeString,
+	},
+	// method_timeouts 는 map 이라 schema 로 표현할 수 없음
 }
 
 func getConfig(connection *plugin.Connection) Config {
 	if connection == nil || connection.Config == nil {
 		return Config{}
 	}
-	config, ok := connection.Config.(Config)
-	if !ok {
-		return Config{}
+	switch config := connection.Config.(type) {
+	case Config:
+		return config
+	case *Config:
+		if config != nil {
+			return *config
+		}
 	}
-	return config
+	return Config{}
 }
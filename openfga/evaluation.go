@@ -0,0 +1,177 @@
+package openfga
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// parseConsistency maps minimize_latency, higher_consistency or unspecified onto the ConsistencyPreference enum.
+func parseConsistency(s string) (openfgav1.ConsistencyPreference, error) {
+	v, ok := openfgav1.ConsistencyPreference_value[strings.ToUpper(strings.TrimSpace(s))]
+	if !ok {
+		return 0, fmt.Errorf("%s must be one of minimize_latency, higher_consistency or unspecified, got %q", consistencyCol, s)
+	}
+	return openfgav1.ConsistencyPreference(v), nil
+}
+
+// consistencyName is the inverse of parseConsistency.
+func consistencyName(c openfgav1.ConsistencyPreference) string {
+	return strings.ToLower(c.String())
+}
+
+// consistencyQual returns the consistency qual, else the connection's default, with the text rows echo:
+// the qual as written, so Postgres' recheck of consistency = 'Minimize_Latency ' still matches.
+func consistencyQual(d *plugin.QueryData, client *Client) (openfgav1.ConsistencyPreference, string, error) {
+	s, err := singleQualString(d, consistencyCol)
+	if err != nil || s == "" {
+		return client.consistency, consistencyName(client.consistency), err
+	}
+	c, err := parseConsistency(s)
+	return c, s, err
+}
+
+// maxContextualTuples is the OpenFGA server limit on contextual tuples per request.
+const maxContextualTuples = 100
+
+// jsonbQual returns the JSONB value given for column with "=", or nil.
+func jsonbQual(d *plugin.QueryData, column string) json.RawMessage {
+	v := d.EqualsQuals[column].GetJsonbValue()
+	if v == "" {
+		return nil
+	}
+	return json.RawMessage(v)
+}
+
+// parseContextualTuples parses contextual tuples in the OpenFGA API format, either as an array of tuple keys
+//
+//	[{"user": "user:anne", "relation": "member", "object": "org:acme"}]
+//
+// or as the {"tuple_keys": [...]} object the API wraps them in.
+func parseContextualTuples(raw json.RawMessage) ([]*openfgav1.TupleKey, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	wrapped := raw
+	if trimmed := strings.TrimSpace(string(raw)); strings.HasPrefix(trimmed, "[") {
+		wrapped = json.RawMessage(`{"tuple_keys":` + trimmed + `}`)
+	}
+
+	var keys openfgav1.ContextualTupleKeys
+	if err := protojson.Unmarshal(wrapped, &keys); err != nil {
+		return nil, fmt.Errorf("%s must be a JSON array of tuple keys like [{\"user\": \"user:anne\", \"relation\": \"member\", \"object\": \"org:acme\"}]: %w", contextualTuplesCol, err)
+	}
+
+	tuples := keys.GetTupleKeys()
+	if len(tuples) > maxContextualTuples {
+		return nil, fmt.Errorf("%s accepts at most %d tuples, got %d", contextualTuplesCol, maxContextualTuples, len(tuples))
+	}
+	for i, t := range tuples {
+		if t.GetUser() == "" || t.GetRelation() == "" || t.GetObject() == "" {
+			return nil, fmt.Errorf("%s[%d]: user, relation and object are required", contextualTuplesCol, i)
+		}
+		if objectType, objectID := splitObject(t.GetObject()); objectType == "" || objectID == "" {
+			return nil, fmt.Errorf("%s[%d]: object %q must be in the form type:id", contextualTuplesCol, i, t.GetObject())
+		}
+		if userType, userID, _ := splitUser(t.GetUser()); userType == "" || userID == "" {
+			return nil, fmt.Errorf("%s[%d]: user %q must be in the form type:id, type:id#relation or type:*", contextualTuplesCol, i, t.GetUser())
+		}
+	}
+	return tuples, nil
+}
+
+// maxExactInteger is the largest integer a google.protobuf.Value, which stores numbers as doubles, holds exactly.
+const maxExactInteger = 1 << 53
+
+// parseContext converts the context qual into the Struct that conditions are evaluated with, e.g.
+//
+//	{"current_time": "2024-01-01T00:00:00Z", "user_ip": "10.0.0.1"}
+//
+// Values keep their JSON types; OpenFGA converts them to the condition parameter types.
+func parseContext(raw json.RawMessage) (*structpb.Struct, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s must be a JSON object: %w", contextCol, err)
+	}
+
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a JSON object such as {\"current_time\": \"2024-01-01T00:00:00Z\"}, got %s", contextCol, jsonTypeName(v))
+	}
+	if _, err := exactNumbers(contextCol, m); err != nil {
+		return nil, err
+	}
+
+	ctx, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", contextCol, err)
+	}
+	return ctx, nil
+}
+
+// exactNumbers replaces json.Number values with float64, rejecting integers a double cannot hold exactly
+// instead of silently rounding them.
+func exactNumbers(path string, v any) (any, error) {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil && (i > maxExactInteger || i < -maxExactInteger) {
+			return nil, fmt.Errorf("%s: integer %s is too large to pass exactly; pass it as a string", path, v)
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return f, nil
+	case map[string]any:
+		for key, item := range v {
+			converted, err := exactNumbers(path+"."+key, item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+		return v, nil
+	case []any:
+		for i, item := range v {
+			converted, err := exactNumbers(path+"["+strconv.Itoa(i)+"]", item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+func jsonTypeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case json.Number, float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []any:
+		return "an array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
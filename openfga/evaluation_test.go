@@ -0,0 +1,135 @@
+package openfga
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestParseContextualTuples(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    int
+		wantErr string
+	}{
+		{"empty", "", 0, ""},
+		{"array", `[{"user": "user:anne", "relation": "member", "object": "org:acme"}]`, 1, ""},
+		{"wrapped", `{"tuple_keys": [{"user": "group:eng#member", "relation": "viewer", "object": "doc:1"}, {"user": "user:*", "relation": "viewer", "object": "doc:2"}]}`, 2, ""},
+		{"with condition", `[{"user": "user:anne", "relation": "member", "object": "org:acme", "condition": {"name": "in_office", "context": {"ip": "10.0.0.1"}}}]`, 1, ""},
+		{"not json", `[{`, 0, "must be a JSON array of tuple keys"},
+		{"unknown field", `[{"subject": "user:anne"}]`, 0, "must be a JSON array of tuple keys"},
+		{"missing relation", `[{"user": "user:anne", "object": "org:acme"}]`, 0, "contextual_tuples[0]: user, relation and object are required"},
+		{"object without id", `[{"user": "user:anne", "relation": "member", "object": "org"}]`, 0, `object "org" must be in the form type:id`},
+		{"user without type", `[{"user": "anne", "relation": "member", "object": "org:acme"}]`, 0, `user "anne" must be in the form`},
+		{"too many", "[" + strings.TrimSuffix(strings.Repeat(`{"user": "user:anne", "relation": "member", "object": "org:acme"},`, maxContextualTuples+1), ",") + "]", 0, "at most 100 tuples"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseContextualTuples(json.RawMessage(tt.raw))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseContextualTuples() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseContextualTuples() error = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("parseContextualTuples() returned %d tuples, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr string
+	}{
+		{"empty", "", ""},
+		{"object", `{"current_time": "2024-01-01T00:00:00Z", "user_ip": "10.0.0.1", "tags": ["a"], "limits": {"max": 10}, "ok": true}`, ""},
+		{"array", `[1, 2]`, "context must be a JSON object such as"},
+		{"string", `"2024-01-01"`, "got a string"},
+		{"null", `null`, "got null"},
+		{"invalid", `{"a":`, "context must be a JSON object"},
+		{"large integer", `{"limits": {"ids": [1, 9007199254740993]}}`, "context.limits.ids[1]: integer 9007199254740993 is too large"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseContext(json.RawMessage(tt.raw))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseContext() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseContext() error = %v", err)
+			}
+			if tt.raw == "" {
+				if got != nil {
+					t.Errorf("parseContext(\"\") = %v, want nil", got)
+				}
+				return
+			}
+			fields := got.GetFields()
+			if fields["current_time"].GetStringValue() != "2024-01-01T00:00:00Z" || fields["limits"].GetStructValue().GetFields()["max"].GetNumberValue() != 10 || !fields["ok"].GetBoolValue() {
+				t.Errorf("parseContext() = %v", got)
+			}
+		})
+	}
+}
+
+func TestParseConsistency(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    openfgav1.ConsistencyPreference
+		wantErr bool
+	}{
+		{"minimize_latency", openfgav1.ConsistencyPreference_MINIMIZE_LATENCY, false},
+		{"HIGHER_CONSISTENCY", openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY, false},
+		{"unspecified", openfgav1.ConsistencyPreference_UNSPECIFIED, false},
+		{"strong", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseConsistency(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConsistency(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if !tt.wantErr && (got != tt.want || consistencyName(got) != strings.ToLower(tt.input)) {
+				t.Errorf("parseConsistency(%q) = %v", tt.input, got)
+			}
+		})
+	}
+
+	if _, err := NewClient(context.Background(), Config{Endpoint: "127.0.0.1:1", Consistency: ptr("eventual")}); err == nil || !strings.Contains(err.Error(), "consistency must be one of") {
+		t.Errorf("NewClient with an invalid consistency: error = %v", err)
+	}
+}
+
+func TestConsistencyQual(t *testing.T) {
+	client := &Client{consistency: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY}
+
+	d := &plugin.QueryData{EqualsQuals: plugin.KeyColumnEqualsQualMap{}}
+	if got, text, err := consistencyQual(d, client); err != nil || got != client.consistency || text != "higher_consistency" {
+		t.Errorf("consistencyQual without a qual = (%v, %q, %v)", got, text, err)
+	}
+
+	// 행은 Postgres 가 다시 검사하므로 qual 을 그대로 돌려준다
+	d.EqualsQuals[consistencyCol] = stringQual(" Minimize_Latency")
+	if got, text, err := consistencyQual(d, client); err != nil || got != openfgav1.ConsistencyPreference_MINIMIZE_LATENCY || text != " Minimize_Latency" {
+		t.Errorf("consistencyQual(%q) = (%v, %q, %v), want the qual echoed as written", " Minimize_Latency", got, text, err)
+	}
+}
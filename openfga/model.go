@@ -0,0 +1,235 @@
+package openfga
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+)
+
+// relationReferenceString renders a directly related user type as a plain type reference:
+// "user", "group#member" or "user:*". The condition guarding it is not included.
+func relationReferenceString(ref *openfgav1.RelationReference) string {
+	s := ref.GetType()
+	switch {
+	case ref.GetWildcard() != nil:
+		s += ":*"
+	case ref.GetRelation() != "":
+		s += "#" + ref.GetRelation()
+	}
+	return s
+}
+
+// rewriteExpression renders a relation rewrite in DSL notation, e.g. "editor or viewer from parent".
+// direct is what the direct assignment ("this") renders as.
+func rewriteExpression(u *openfgav1.Userset, direct string) string {
+	switch v := u.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		return direct
+	case *openfgav1.Userset_ComputedUserset:
+		return v.ComputedUserset.GetRelation()
+	case *openfgav1.Userset_TupleToUserset:
+		return v.TupleToUserset.GetComputedUserset().GetRelation() + " from " + v.TupleToUserset.GetTupleset().GetRelation()
+	case *openfgav1.Userset_Union:
+		return joinRewrites(v.Union.GetChild(), " or ", direct)
+	case *openfgav1.Userset_Intersection:
+		return joinRewrites(v.Intersection.GetChild(), " and ", direct)
+	case *openfgav1.Userset_Difference:
+		return nestedRewrite(v.Difference.GetBase(), direct) + " but not " + nestedRewrite(v.Difference.GetSubtract(), direct)
+	default:
+		return ""
+	}
+}
+
+func joinRewrites(children []*openfgav1.Userset, sep, direct string) string {
+	parts := make([]string, 0, len(children))
+	for _, child := range children {
+		parts = append(parts, nestedRewrite(child, direct))
+	}
+	return strings.Join(parts, sep)
+}
+
+// nestedRewrite wraps set operations in parentheses so mixed operators keep their grouping.
+func nestedRewrite(u *openfgav1.Userset, direct string) string {
+	switch u.GetUserset().(type) {
+	case *openfgav1.Userset_Union, *openfgav1.Userset_Intersection, *openfgav1.Userset_Difference:
+		return "(" + rewriteExpression(u, direct) + ")"
+	default:
+		return rewriteExpression(u, direct)
+	}
+}
+
+// rewriteKind names the top-level operator of a rewrite.
+func rewriteKind(u *openfgav1.Userset) string {
+	switch u.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		return "this"
+	case *openfgav1.Userset_ComputedUserset:
+		return "computed_userset"
+	case *openfgav1.Userset_TupleToUserset:
+		return "tuple_to_userset"
+	case *openfgav1.Userset_Union:
+		return "union"
+	case *openfgav1.Userset_Intersection:
+		return "intersection"
+	case *openfgav1.Userset_Difference:
+		return "difference"
+	default:
+		return ""
+	}
+}
+
+// modelDSL renders an authorization model in the OpenFGA DSL, e.g.
+//
+//	model
+//	  schema 1.1
+//
+//	type document
+//	  relations
+//	    define viewer: [user, group#member] or editor
+//
+// It returns "" for models the DSL cannot express as returned by the API: schema 1.0 models, and models whose
+// direct assignments lack the metadata naming their user types.
+//
+// The API does not keep the declaration order of relations, so each relation is written after the relations of its
+// type that it references, the way models are usually declared, and otherwise in name order. Conditions are written
+// in name order.
+func modelDSL(model *openfgav1.AuthorizationModel) string {
+	if model.GetSchemaVersion() != "1.1" {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("model\n  schema " + model.GetSchemaVersion() + "\n")
+
+	for _, typeDef := range model.GetTypeDefinitions() {
+		b.WriteString("\ntype " + typeDef.GetType() + "\n")
+		if len(typeDef.GetRelations()) == 0 {
+			continue
+		}
+
+		b.WriteString("  relations\n")
+		for _, name := range relationOrder(typeDef.GetRelations()) {
+			rewrite := typeDef.GetRelations()[name]
+			refs := typeDef.GetMetadata().GetRelations()[name].GetDirectlyRelatedUserTypes()
+			// 직접 할당에 타입이 없으면 "[]" 가 되어 DSL 로 읽을 수 없다
+			if len(refs) == 0 && hasDirect(rewrite) {
+				return ""
+			}
+			b.WriteString("    define " + name + ": " + rewriteExpression(rewrite, directlyRelatedDSL(refs)) + "\n")
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(model.GetConditions())) {
+		b.WriteString("\n" + conditionDSL(model.GetConditions()[name]) + "\n")
+	}
+	return b.String()
+}
+
+// relationOrder orders the relations of a type so each follows the relations it references, ties in name order.
+// A cycle, such as "viewer from parent" on viewer itself, keeps the first relation reached.
+func relationOrder(relations map[string]*openfgav1.Userset) []string {
+	order := make([]string, 0, len(relations))
+	seen := make(map[string]bool, len(relations))
+
+	var visit func(name string)
+	visit = func(name string) {
+		if seen[name] {
+			return
+		}
+		seen[name] = true
+		for _, ref := range slices.Sorted(maps.Keys(referencedRelations(relations[name], map[string]bool{}))) {
+			if _, ok := relations[ref]; ok {
+				visit(ref)
+			}
+		}
+		order = append(order, name)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(relations)) {
+		visit(name)
+	}
+	return order
+}
+
+// referencedRelations adds the relations of the same type a rewrite refers to: computed usersets and tuplesets.
+func referencedRelations(u *openfgav1.Userset, refs map[string]bool) map[string]bool {
+	switch v := u.GetUserset().(type) {
+	case *openfgav1.Userset_ComputedUserset:
+		refs[v.ComputedUserset.GetRelation()] = true
+	case *openfgav1.Userset_TupleToUserset:
+		refs[v.TupleToUserset.GetTupleset().GetRelation()] = true
+	case *openfgav1.Userset_Union:
+		for _, child := range v.Union.GetChild() {
+			referencedRelations(child, refs)
+		}
+	case *openfgav1.Userset_Intersection:
+		for _, child := range v.Intersection.GetChild() {
+			referencedRelations(child, refs)
+		}
+	case *openfgav1.Userset_Difference:
+		referencedRelations(v.Difference.GetBase(), refs)
+		referencedRelations(v.Difference.GetSubtract(), refs)
+	}
+	return refs
+}
+
+// hasDirect reports whether a rewrite includes direct assignment ("this").
+func hasDirect(u *openfgav1.Userset) bool {
+	switch v := u.GetUserset().(type) {
+	case *openfgav1.Userset_This:
+		return true
+	case *openfgav1.Userset_Union:
+		return slices.ContainsFunc(v.Union.GetChild(), hasDirect)
+	case *openfgav1.Userset_Intersection:
+		return slices.ContainsFunc(v.Intersection.GetChild(), hasDirect)
+	case *openfgav1.Userset_Difference:
+		return hasDirect(v.Difference.GetBase()) || hasDirect(v.Difference.GetSubtract())
+	default:
+		return false
+	}
+}
+
+// directlyRelatedDSL renders the direct assignment of a relation, e.g. "[user, group#member, user:* with non_expired]".
+func directlyRelatedDSL(refs []*openfgav1.RelationReference) string {
+	parts := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		part := relationReferenceString(ref)
+		if ref.GetCondition() != "" {
+			part += " with " + ref.GetCondition()
+		}
+		parts = append(parts, part)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// conditionDSL renders a condition with its parameter list and CEL expression.
+func conditionDSL(condition *openfgav1.Condition) string {
+	params := make([]string, 0, len(condition.GetParameters()))
+	for _, name := range slices.Sorted(maps.Keys(condition.GetParameters())) {
+		params = append(params, name+": "+conditionParamTypeDSL(condition.GetParameters()[name]))
+	}
+
+	var b strings.Builder
+	b.WriteString("condition " + condition.GetName() + "(" + strings.Join(params, ", ") + ") {\n")
+	for _, line := range strings.Split(strings.TrimSpace(condition.GetExpression()), "\n") {
+		b.WriteString("  " + strings.TrimSpace(line) + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// conditionParamTypeDSL renders a parameter type, e.g. "timestamp" or "map<list<string>>".
+func conditionParamTypeDSL(ref *openfgav1.ConditionParamTypeRef) string {
+	name := strings.ToLower(strings.TrimPrefix(ref.GetTypeName().String(), "TYPE_NAME_"))
+	if len(ref.GetGenericTypes()) == 0 {
+		return name
+	}
+
+	generics := make([]string, 0, len(ref.GetGenericTypes()))
+	for _, g := range ref.GetGenericTypes() {
+		generics = append(generics, conditionParamTypeDSL(g))
+	}
+	return name + "<" + strings.Join(generics, ", ") + ">"
+}
@@ -0,0 +1,266 @@
+package openfga
+
+import (
+	"slices"
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+func this() *openfgav1.Userset {
+	return &openfgav1.Userset{Userset: &openfgav1.Userset_This{This: &openfgav1.DirectUserset{}}}
+}
+
+func computed(relation string) *openfgav1.Userset {
+	return &openfgav1.Userset{Userset: &openfgav1.Userset_ComputedUserset{
+		ComputedUserset: &openfgav1.ObjectRelation{Relation: relation},
+	}}
+}
+
+func tupleToUserset(tupleset, relation string) *openfgav1.Userset {
+	return &openfgav1.Userset{Userset: &openfgav1.Userset_TupleToUserset{TupleToUserset: &openfgav1.TupleToUserset{
+		Tupleset:        &openfgav1.ObjectRelation{Relation: tupleset},
+		ComputedUserset: &openfgav1.ObjectRelation{Relation: relation},
+	}}}
+}
+
+func union(children ...*openfgav1.Userset) *openfgav1.Userset {
+	return &openfgav1.Userset{Userset: &openfgav1.Userset_Union{Union: &openfgav1.Usersets{Child: children}}}
+}
+
+func intersection(children ...*openfgav1.Userset) *openfgav1.Userset {
+	return &openfgav1.Userset{Userset: &openfgav1.Userset_Intersection{Intersection: &openfgav1.Usersets{Child: children}}}
+}
+
+func difference(base, subtract *openfgav1.Userset) *openfgav1.Userset {
+	return &openfgav1.Userset{Userset: &openfgav1.Userset_Difference{Difference: &openfgav1.Difference{Base: base, Subtract: subtract}}}
+}
+
+func TestRewriteExpression(t *testing.T) {
+	tests := []struct {
+		name    string
+		userset *openfgav1.Userset
+		want    string
+	}{
+		{"this", this(), "this"},
+		{"computed userset", computed("editor"), "editor"},
+		{"tuple to userset", tupleToUserset("parent", "viewer"), "viewer from parent"},
+		{"union", union(this(), computed("editor"), tupleToUserset("parent", "viewer")), "this or editor or viewer from parent"},
+		{"intersection", intersection(computed("member"), computed("allowed")), "member and allowed"},
+		{"difference", difference(this(), computed("blocked")), "this but not blocked"},
+		{"nested", difference(union(this(), computed("editor")), computed("blocked")), "(this or editor) but not blocked"},
+		{"empty", &openfgav1.Userset{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rewriteExpression(tt.userset, "this"); got != tt.want {
+				t.Errorf("rewriteExpression() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRelationRow(t *testing.T) {
+	typeDef := &openfgav1.TypeDefinition{
+		Type: "document",
+		Relations: map[string]*openfgav1.Userset{
+			"viewer": union(this(), computed("editor")),
+		},
+		Metadata: &openfgav1.Metadata{Relations: map[string]*openfgav1.RelationMetadata{
+			"viewer": {DirectlyRelatedUserTypes: []*openfgav1.RelationReference{
+				{Type: "user"},
+				{Type: "group", RelationOrWildcard: &openfgav1.RelationReference_Relation{Relation: "member"}},
+				{Type: "user", RelationOrWildcard: &openfgav1.RelationReference_Wildcard{Wildcard: &openfgav1.Wildcard{}}, Condition: "non_expired"},
+				{Type: "group", RelationOrWildcard: &openfgav1.RelationReference_Relation{Relation: "member"}, Condition: "non_expired"},
+			}},
+		}},
+	}
+
+	row := newRelationRow("01ARZ3NDEKTSV4RRFFQ69G5FAV", typeDef, "viewer")
+
+	wantTypes := []string{"user", "group#member", "user:*"}
+	if !slices.Equal(row.DirectlyRelatedUserTypes, wantTypes) {
+		t.Errorf("DirectlyRelatedUserTypes = %v, want %v", row.DirectlyRelatedUserTypes, wantTypes)
+	}
+	if !row.AcceptsWildcard || !row.HasCondition {
+		t.Errorf("AcceptsWildcard = %v, HasCondition = %v, want both true", row.AcceptsWildcard, row.HasCondition)
+	}
+	if !slices.Equal(row.Conditions, []string{"non_expired"}) {
+		t.Errorf("Conditions = %v", row.Conditions)
+	}
+	if row.RewriteKind != "union" || row.Rewrite != "this or editor" {
+		t.Errorf("rewrite = %q (%s), want %q (union)", row.Rewrite, row.RewriteKind, "this or editor")
+	}
+}
+
+func TestModelDSL(t *testing.T) {
+	model := &openfgav1.AuthorizationModel{
+		SchemaVersion: "1.1",
+		TypeDefinitions: []*openfgav1.TypeDefinition{
+			{Type: "user"},
+			{
+				Type:      "group",
+				Relations: map[string]*openfgav1.Userset{"member": this()},
+				Metadata: &openfgav1.Metadata{Relations: map[string]*openfgav1.RelationMetadata{
+					"member": {DirectlyRelatedUserTypes: []*openfgav1.RelationReference{{Type: "user"}}},
+				}},
+			},
+			{
+				Type: "document",
+				Relations: map[string]*openfgav1.Userset{
+					"parent": this(),
+					"editor": this(),
+					"viewer": union(this(), computed("editor"), tupleToUserset("parent", "viewer")),
+				},
+				Metadata: &openfgav1.Metadata{Relations: map[string]*openfgav1.RelationMetadata{
+					"parent": {DirectlyRelatedUserTypes: []*openfgav1.RelationReference{{Type: "document"}}},
+					"editor": {DirectlyRelatedUserTypes: []*openfgav1.RelationReference{{Type: "user"}}},
+					"viewer": {DirectlyRelatedUserTypes: []*openfgav1.RelationReference{
+						{Type: "group", RelationOrWildcard: &openfgav1.RelationReference_Relation{Relation: "member"}},
+						{Type: "user", RelationOrWildcard: &openfgav1.RelationReference_Wildcard{Wildcard: &openfgav1.Wildcard{}}, Condition: "non_expired"},
+					}},
+				}},
+			},
+		},
+		Conditions: map[string]*openfgav1.Condition{
+			"non_expired": {
+				Name:       "non_expired",
+				Expression: "current_time < expiration",
+				Parameters: map[string]*openfgav1.ConditionParamTypeRef{
+					"current_time": {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+					"expiration":   {TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_TIMESTAMP},
+					"tags": {
+						TypeName:     openfgav1.ConditionParamTypeRef_TYPE_NAME_MAP,
+						GenericTypes: []*openfgav1.ConditionParamTypeRef{{TypeName: openfgav1.ConditionParamTypeRef_TYPE_NAME_STRING}},
+					},
+				},
+			},
+		},
+	}
+
+	want := `model
+  schema 1.1
+
+type user
+
+type group
+  relations
+    define member: [user]
+
+type document
+  relations
+    define editor: [user]
+    define parent: [document]
+    define viewer: [group#member, user:* with non_expired] or editor or viewer from parent
+
+condition non_expired(current_time: timestamp, expiration: timestamp, tags: map<string>) {
+  current_time < expiration
+}
+`
+	if got := modelDSL(model); got != want {
+		t.Errorf("modelDSL() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// TestModelDSL_RoundTrip renders a model as the API returns it and compares the result with the DSL it was written in.
+func TestModelDSL_RoundTrip(t *testing.T) {
+	source := `model
+  schema 1.1
+
+type user
+
+type folder
+  relations
+    define owner: [user]
+    define viewer: [user] or owner
+
+type document
+  relations
+    define owner: [user]
+    define editor: [user with non_expired] or owner
+    define parent: [folder]
+    define viewer: [user, user:*] or editor or viewer from parent
+
+condition non_expired(current_time: timestamp, expiration: timestamp) {
+  current_time < expiration
+}
+`
+	// fga model transform 의 출력과 같은 JSON
+	modelJSON := `{
+  "schema_version": "1.1",
+  "type_definitions": [
+    {"type": "user"},
+    {
+      "type": "folder",
+      "relations": {
+        "owner": {"this": {}},
+        "viewer": {"union": {"child": [{"this": {}}, {"computedUserset": {"relation": "owner"}}]}}
+      },
+      "metadata": {"relations": {
+        "owner": {"directly_related_user_types": [{"type": "user"}]},
+        "viewer": {"directly_related_user_types": [{"type": "user"}]}
+      }}
+    },
+    {
+      "type": "document",
+      "relations": {
+        "owner": {"this": {}},
+        "editor": {"union": {"child": [{"this": {}}, {"computedUserset": {"relation": "owner"}}]}},
+        "parent": {"this": {}},
+        "viewer": {"union": {"child": [
+          {"this": {}},
+          {"computedUserset": {"relation": "editor"}},
+          {"tupleToUserset": {"tupleset": {"relation": "parent"}, "computedUserset": {"relation": "viewer"}}}
+        ]}}
+      },
+      "metadata": {"relations": {
+        "owner": {"directly_related_user_types": [{"type": "user"}]},
+        "editor": {"directly_related_user_types": [{"type": "user", "condition": "non_expired"}]},
+        "parent": {"directly_related_user_types": [{"type": "folder"}]},
+        "viewer": {"directly_related_user_types": [{"type": "user"}, {"type": "user", "wildcard": {}}]}
+      }}
+    }
+  ],
+  "conditions": {
+    "non_expired": {
+      "name": "non_expired",
+      "expression": "current_time < expiration",
+      "parameters": {
+        "current_time": {"type_name": "TYPE_NAME_TIMESTAMP"},
+        "expiration": {"type_name": "TYPE_NAME_TIMESTAMP"}
+      }
+    }
+  }
+}`
+
+	model := &openfgav1.AuthorizationModel{}
+	if err := protojson.Unmarshal([]byte(modelJSON), model); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got := modelDSL(model); got != source {
+		t.Errorf("modelDSL() =\n%s\nwant\n%s", got, source)
+	}
+}
+
+func TestModelDSL_Unrenderable(t *testing.T) {
+	tests := map[string]*openfgav1.AuthorizationModel{
+		"schema 1.0": {
+			SchemaVersion:   "1.0",
+			TypeDefinitions: []*openfgav1.TypeDefinition{{Type: "document", Relations: map[string]*openfgav1.Userset{"viewer": this()}}},
+		},
+		"no metadata": {
+			SchemaVersion:   "1.1",
+			TypeDefinitions: []*openfgav1.TypeDefinition{{Type: "document", Relations: map[string]*openfgav1.Userset{"viewer": union(this(), computed("editor")), "editor": computed("viewer")}}},
+		},
+	}
+
+	for name, model := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := modelDSL(model); got != "" {
+				t.Errorf("modelDSL() = %q, want \"\"", got)
+			}
+		})
+	}
+}
@@ -19,6 +19,17 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 		},
 		TableMap: map[string]*plugin.Table{
 			"sys_acl_permission": tableAclPermission(ctx),
+			"openfga_store":      tableOpenFGAStore(ctx),
+
+			"openfga_connection_status": tableOpenFGAConnectionStatus(ctx),
+
+			"openfga_authorization_model": tableOpenFGAAuthorizationModel(ctx),
+			"openfga_type_definition":     tableOpenFGATypeDefinition(ctx),
+			"openfga_relation":            tableOpenFGARelation(ctx),
+			"openfga_tuple":               tableOpenFGATuple(ctx),
+			"openfga_change":              tableOpenFGAChange(ctx),
+			"openfga_assertion":           tableOpenFGAAssertion(ctx),
+			"openfga_expand":              tableOpenFGAExpand(ctx),
 		},
 	}
 }
@@ -0,0 +1,373 @@
+package openfga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultMaxRetries      = 3
+	defaultRetryBackoff    = 100 * time.Millisecond
+	defaultRetryMaxBackoff = 5 * time.Second
+	defaultRequestTimeout  = 30 * time.Second
+)
+
+// retryableMethods are the read RPCs that are safe to send again. Writes are never retried.
+// Full method names keep RPCs of other services, such as the health Check, out.
+var retryableMethods = []string{
+	openfgav1.OpenFGAService_Check_FullMethodName,
+	openfgav1.OpenFGAService_BatchCheck_FullMethodName,
+	openfgav1.OpenFGAService_ListObjects_FullMethodName,
+	openfgav1.OpenFGAService_ListUsers_FullMethodName,
+	openfgav1.OpenFGAService_Read_FullMethodName,
+	openfgav1.OpenFGAService_Expand_FullMethodName,
+	openfgav1.OpenFGAService_ReadChanges_FullMethodName,
+	openfgav1.OpenFGAService_ReadAuthorizationModel_FullMethodName,
+	openfgav1.OpenFGAService_ReadAuthorizationModels_FullMethodName,
+	openfgav1.OpenFGAService_ReadAssertions_FullMethodName,
+	openfgav1.OpenFGAService_GetStore_FullMethodName,
+	openfgav1.OpenFGAService_ListStores_FullMethodName,
+	openfgav1.OpenFGAService_StreamedListObjects_FullMethodName,
+}
+
+// retryPolicy bounds every RPC with a timeout and retries the retryableMethods on transient errors
+// with exponential backoff and full jitter, or after the delay the server asks for, capped at maxBackoff.
+// A stream is only retried until its first message arrives; after that a retry would repeat rows.
+type retryPolicy struct {
+	maxRetries int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	// timeout applies to each attempt; methodTimeouts overrides it per full method name, 0 disables it
+	timeout        time.Duration
+	methodTimeouts map[string]time.Duration
+}
+
+func newRetryPolicy(cfg Config) (*retryPolicy, error) {
+	p := &retryPolicy{
+		maxRetries:     defaultMaxRetries,
+		backoff:        defaultRetryBackoff,
+		maxBackoff:     defaultRetryMaxBackoff,
+		timeout:        defaultRequestTimeout,
+		methodTimeouts: map[string]time.Duration{},
+	}
+
+	if cfg.MaxRetries != nil {
+		if *cfg.MaxRetries < 0 {
+			return nil, fmt.Errorf("max_retries must not be negative: %d", *cfg.MaxRetries)
+		}
+		p.maxRetries = *cfg.MaxRetries
+	}
+
+	for _, opt := range []struct {
+		name  string
+		value *string
+		dst   *time.Duration
+	}{
+		{"retry_backoff", cfg.RetryBackoff, &p.backoff},
+		{"retry_max_backoff", cfg.RetryMaxBackoff, &p.maxBackoff},
+		{"request_timeout", cfg.RequestTimeout, &p.timeout},
+	} {
+		if opt.value == nil {
+			continue
+		}
+		d, err := parseDurationOption(opt.name, *opt.value)
+		if err != nil {
+			return nil, err
+		}
+		*opt.dst = d
+	}
+	if p.backoff > p.maxBackoff {
+		return nil, fmt.Errorf("retry_backoff %s exceeds retry_max_backoff %s", p.backoff, p.maxBackoff)
+	}
+
+	for method, value := range cfg.MethodTimeouts {
+		fullMethod, ok := serviceMethod(method)
+		if !ok {
+			return nil, fmt.Errorf("method_timeouts: unknown OpenFGA RPC %q, use names such as Check or StreamedListObjects", method)
+		}
+		d, err := parseDurationOption("method_timeouts."+method, value)
+		if err != nil {
+			return nil, err
+		}
+		p.methodTimeouts[fullMethod] = d
+	}
+	return p, nil
+}
+
+func parseDurationOption(name, value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid duration %q, use values such as 500ms or 30s", name, value)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s must not be negative: %s", name, value)
+	}
+	return d, nil
+}
+
+// serviceMethod returns the full method name of the OpenFGA RPC called name, e.g. Check or StreamedListObjects.
+func serviceMethod(name string) (string, bool) {
+	desc := openfgav1.OpenFGAService_ServiceDesc
+	found := slices.ContainsFunc(desc.Methods, func(m grpc.MethodDesc) bool { return m.MethodName == name }) ||
+		slices.ContainsFunc(desc.Streams, func(s grpc.StreamDesc) bool { return s.StreamName == name })
+	if !found {
+		return "", false
+	}
+	return "/" + desc.ServiceName + "/" + name, true
+}
+
+func (p *retryPolicy) timeoutFor(method string) time.Duration {
+	if d, ok := p.methodTimeouts[method]; ok {
+		return d
+	}
+	return p.timeout
+}
+
+// backoffFor returns the wait before retry attempt+1: a random duration up to backoff * 2^attempt, capped at maxBackoff.
+func (p *retryPolicy) backoffFor(attempt int) time.Duration {
+	d := p.backoff
+	for i := 0; i < attempt && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, p.maxBackoff)
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d) + 1
+}
+
+func (p *retryPolicy) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	retries := 0
+	if slices.Contains(retryableMethods, method) {
+		retries = p.maxRetries
+	}
+
+	for attempt := 0; ; attempt++ {
+		var header, trailer metadata.MD
+		callOpts := append(slices.Clip(opts), grpc.Header(&header), grpc.Trailer(&trailer))
+
+		err := p.invoke(ctx, p.timeoutFor(method), method, req, reply, cc, invoker, callOpts...)
+		if err == nil || attempt >= retries || !isRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if !p.pause(ctx, attempt, err, header, trailer) {
+			return err
+		}
+	}
+}
+
+// pause waits before retry attempt+1 and reports whether the retry can still be sent before ctx ends.
+func (p *retryPolicy) pause(ctx context.Context, attempt int, err error, header, trailer metadata.MD) bool {
+	wait := p.backoffFor(attempt)
+	if hint, ok := retryHint(err, header, trailer); ok {
+		wait = min(hint, p.maxBackoff)
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		// 기다려도 deadline 전에 재시도할 수 없다.
+		return false
+	}
+
+	timer := time.NewTimer(wait)
+	select {
+	case <-ctx.Done():
+		timer.Stop()
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func (p *retryPolicy) invoke(ctx context.Context, timeout time.Duration, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func (p *retryPolicy) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	s := &retryingStream{policy: p, ctx: ctx, desc: desc, cc: cc, method: method, streamer: streamer, opts: opts}
+	// a client-streaming request cannot be replayed
+	if slices.Contains(retryableMethods, method) && !desc.ClientStreams {
+		s.retries = p.maxRetries
+	}
+
+	if err := s.open(); err != nil {
+		if !s.retryable(err) {
+			return nil, err
+		}
+		if err := s.retry(err, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// retryingStream bounds the wait for the first message of each attempt with the method timeout and, until that
+// message arrives, reopens the stream on transient errors and replays the request. Once results flow the stream
+// runs as long as the caller's context, so a long listing is not cut off mid-stream.
+type retryingStream struct {
+	grpc.ClientStream
+	attemptCtx context.Context
+	cancel     context.CancelFunc
+	timer      *time.Timer
+
+	policy   *retryPolicy
+	ctx      context.Context
+	desc     *grpc.StreamDesc
+	cc       *grpc.ClientConn
+	method   string
+	streamer grpc.Streamer
+	opts     []grpc.CallOption
+
+	retries  int
+	attempt  int
+	request  any
+	closed   bool
+	received bool
+}
+
+// errFirstMessageTimeout is the cause an attempt is cancelled with when no message arrives within the timeout.
+var errFirstMessageTimeout = errors.New("no message within the request timeout")
+
+// open starts an attempt and replays what the caller already sent on the previous one.
+// The attempt context derives from the caller's, so it ends with it as well as with cancel.
+func (s *retryingStream) open() error {
+	ctx, cancel := context.WithCancelCause(s.ctx)
+	s.attemptCtx, s.timer = ctx, nil
+	s.cancel = func() {
+		if s.timer != nil {
+			s.timer.Stop()
+		}
+		cancel(context.Canceled)
+	}
+	if timeout := s.policy.timeoutFor(s.method); timeout > 0 {
+		s.timer = time.AfterFunc(timeout, func() { cancel(errFirstMessageTimeout) })
+	}
+
+	stream, err := s.streamer(ctx, s.desc, s.cc, s.method, s.opts...)
+	if err != nil {
+		err = s.timeoutErr(err)
+		s.cancel()
+		return err
+	}
+	s.ClientStream = stream
+
+	// SendMsg 가 실패하면 그 status 는 RecvMsg 가 돌려준다.
+	if s.request != nil {
+		_ = stream.SendMsg(s.request)
+	}
+	if s.closed {
+		_ = stream.CloseSend()
+	}
+	return nil
+}
+
+// timeoutErr reports an attempt cancelled by the first message timeout as DeadlineExceeded, like a unary timeout.
+func (s *retryingStream) timeoutErr(err error) error {
+	if err != nil && errors.Is(context.Cause(s.attemptCtx), errFirstMessageTimeout) && s.ctx.Err() == nil {
+		return status.Error(codes.DeadlineExceeded, errFirstMessageTimeout.Error())
+	}
+	return err
+}
+
+func (s *retryingStream) retryable(err error) bool {
+	return !s.received && s.attempt < s.retries && isRetryable(err) && s.ctx.Err() == nil
+}
+
+// retry waits as the policy asks and opens the next attempt, returning err once the policy gives up.
+func (s *retryingStream) retry(err error, header, trailer metadata.MD) error {
+	for {
+		if !s.policy.pause(s.ctx, s.attempt, err, header, trailer) {
+			return err
+		}
+		s.attempt++
+		if err = s.open(); err == nil || !s.retryable(err) {
+			return err
+		}
+		header, trailer = nil, nil
+	}
+}
+
+func (s *retryingStream) SendMsg(m any) error {
+	if !s.desc.ClientStreams {
+		s.request = m
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *retryingStream) CloseSend() error {
+	s.closed = true
+	return s.ClientStream.CloseSend()
+}
+
+func (s *retryingStream) RecvMsg(m any) error {
+	for {
+		err := s.ClientStream.RecvMsg(m)
+		if err == nil {
+			// 첫 메시지가 오면 timeout 을 해제한다. 이후는 호출자의 context 만 적용된다.
+			if !s.received && s.timer != nil {
+				s.timer.Stop()
+			}
+			s.received = true
+			return nil
+		}
+		err = s.timeoutErr(err)
+		// io.EOF 를 포함해 스트림이 끝났다.
+		if !s.retryable(err) {
+			s.cancel()
+			return err
+		}
+
+		header, _ := s.ClientStream.Header()
+		trailer := s.ClientStream.Trailer()
+		s.cancel()
+		if err := s.retry(err, header, trailer); err != nil {
+			return err
+		}
+	}
+}
+
+// isRetryable reports whether the error is transient. Timeouts are not retried, a slow server would only get slower.
+func isRetryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// retryHint returns the delay the server asked for, from a google.rpc.RetryInfo detail or a retry-after
+// header or trailer in seconds.
+func retryHint(err error, header, trailer metadata.MD) (time.Duration, bool) {
+	if s, ok := status.FromError(err); ok {
+		for _, detail := range s.Details() {
+			if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+				return info.GetRetryDelay().AsDuration(), true
+			}
+		}
+	}
+
+	for _, md := range []metadata.MD{header, trailer} {
+		if values := md.Get("retry-after"); len(values) > 0 {
+			if seconds, err := strconv.Atoi(strings.TrimSpace(values[0])); err == nil && seconds >= 0 {
+				return time.Duration(seconds) * time.Second, true
+			}
+		}
+	}
+	return 0, false
+}
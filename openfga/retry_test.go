@@ -0,0 +1,422 @@
+package openfga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// flakyServer fails the first failures calls of each RPC with code, optionally asking to retry after hint.
+type flakyServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	failures int32
+	code     codes.Code
+	hint     time.Duration
+	delay    time.Duration
+	calls    atomic.Int32
+
+	// breakMidStream makes StreamedListObjects fail with code after its first object
+	breakMidStream bool
+	// interval is how long StreamedListObjects waits between objects
+	interval time.Duration
+	mu       sync.Mutex
+	users    []string
+}
+
+func (s *flakyServer) fail() error {
+	if s.calls.Add(1) > s.failures {
+		return nil
+	}
+	st := status.New(s.code, "try again")
+	if s.hint > 0 {
+		st, _ = st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(s.hint)})
+	}
+	return st.Err()
+}
+
+func (s *flakyServer) Check(ctx context.Context, _ *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &openfgav1.CheckResponse{Allowed: true}, nil
+}
+
+func (s *flakyServer) ListUsers(ctx context.Context, _ *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, error) {
+	if s.calls.Add(1) <= s.failures {
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", "1"))
+		return nil, status.Error(s.code, "rate limited")
+	}
+	return &openfgav1.ListUsersResponse{}, nil
+}
+
+func (s *flakyServer) ListObjects(ctx context.Context, _ *openfgav1.ListObjectsRequest) (*openfgav1.ListObjectsResponse, error) {
+	s.calls.Add(1)
+	select {
+	case <-time.After(s.delay):
+	case <-ctx.Done():
+	}
+	return &openfgav1.ListObjectsResponse{}, nil
+}
+
+func (s *flakyServer) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest, stream openfgav1.OpenFGAService_StreamedListObjectsServer) error {
+	s.mu.Lock()
+	s.users = append(s.users, req.GetUser())
+	s.mu.Unlock()
+
+	if err := s.fail(); err != nil {
+		return err
+	}
+	select {
+	case <-time.After(s.delay):
+	case <-stream.Context().Done():
+		return stream.Context().Err()
+	}
+
+	for i := range 2 {
+		if err := stream.Send(&openfgav1.StreamedListObjectsResponse{Object: fmt.Sprintf("doc:%d", i)}); err != nil {
+			return err
+		}
+		if s.breakMidStream {
+			return status.Error(s.code, "connection reset")
+		}
+		if i == 0 && s.interval > 0 {
+			time.Sleep(s.interval)
+		}
+	}
+	return nil
+}
+
+func (s *flakyServer) Write(ctx context.Context, _ *openfgav1.WriteRequest) (*openfgav1.WriteResponse, error) {
+	if err := s.fail(); err != nil {
+		return nil, err
+	}
+	return &openfgav1.WriteResponse{}, nil
+}
+
+func newRetryTestClient(t *testing.T, srv *flakyServer, cfg Config) *Client {
+	t.Helper()
+	cfg.Endpoint = startTestServer(t, srv)
+	cfg.StoreId = ptr("store")
+	if cfg.RetryBackoff == nil {
+		cfg.RetryBackoff, cfg.RetryMaxBackoff = ptr("1ms"), ptr("2ms")
+	}
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// listFirstObject runs listObjects through client like a query with LIMIT 1 and returns the object ids it streamed.
+func listFirstObject(t *testing.T, client *Client) ([]string, error) {
+	t.Helper()
+	clientCache.Store(t.Name(), client)
+	t.Cleanup(clearClientCache)
+
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+
+	d := &plugin.QueryData{
+		Connection:  &plugin.Connection{Name: t.Name()},
+		EqualsQuals: plugin.KeyColumnEqualsQualMap{},
+	}
+	var objects []string
+	d.StreamListItem = func(_ context.Context, items ...any) {
+		objects = append(objects, items[0].(AclPermissionRow).ObjectID)
+		// RowsRemaining 은 context 가 끝나면 0 을 돌려준다.
+		cancel()
+	}
+
+	_, err := listObjects(ctx, d, "doc", "user", "anne", "", "viewer")
+	return objects, err
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("transient errors are retried", func(t *testing.T) {
+		srv := &flakyServer{failures: 2, code: codes.Unavailable}
+		client := newRetryTestClient(t, srv, Config{})
+
+		if _, err := client.Check(context.Background(), testCheckRequest()); err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if got := srv.calls.Load(); got != 3 {
+			t.Fatalf("Expected 3 attempts, got %d", got)
+		}
+	})
+
+	t.Run("retries are bounded by max_retries", func(t *testing.T) {
+		srv := &flakyServer{failures: 10, code: codes.ResourceExhausted}
+		client := newRetryTestClient(t, srv, Config{MaxRetries: ptr(1)})
+
+		if _, err := client.Check(context.Background(), testCheckRequest()); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("Expected ResourceExhausted, got %v", err)
+		}
+		if got := srv.calls.Load(); got != 2 {
+			t.Fatalf("Expected 2 attempts, got %d", got)
+		}
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, code: codes.InvalidArgument}
+		client := newRetryTestClient(t, srv, Config{})
+
+		if _, err := client.Check(context.Background(), testCheckRequest()); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("Expected InvalidArgument, got %v", err)
+		}
+		if got := srv.calls.Load(); got != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", got)
+		}
+	})
+
+	t.Run("writes are not retried", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, code: codes.Unavailable}
+		client := newRetryTestClient(t, srv, Config{})
+
+		if _, err := client.Write(context.Background(), &openfgav1.WriteRequest{StoreId: "store"}); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Expected Unavailable, got %v", err)
+		}
+		if got := srv.calls.Load(); got != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", got)
+		}
+	})
+
+	t.Run("RetryInfo delay is honoured", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, code: codes.ResourceExhausted, hint: 200 * time.Millisecond}
+		client := newRetryTestClient(t, srv, Config{RetryBackoff: ptr("1ms"), RetryMaxBackoff: ptr("1s")})
+
+		start := time.Now()
+		if _, err := client.Check(context.Background(), testCheckRequest()); err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < srv.hint {
+			t.Fatalf("Expected to wait at least %s, retried after %s", srv.hint, elapsed)
+		}
+	})
+
+	t.Run("retry-after header is honoured", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, code: codes.ResourceExhausted}
+		client := newRetryTestClient(t, srv, Config{RetryBackoff: ptr("1ms"), RetryMaxBackoff: ptr("2s")})
+
+		start := time.Now()
+		if _, err := client.ListUsers(context.Background(), &openfgav1.ListUsersRequest{StoreId: "store"}); err != nil {
+			t.Fatalf("ListUsers failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed < time.Second {
+			t.Fatalf("Expected to wait at least 1s, retried after %s", elapsed)
+		}
+	})
+
+	t.Run("delay hints are capped at retry_max_backoff", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, code: codes.ResourceExhausted, hint: time.Minute}
+		client := newRetryTestClient(t, srv, Config{})
+
+		start := time.Now()
+		if _, err := client.Check(context.Background(), testCheckRequest()); err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Expected the 1m hint to be capped at 2ms, retried after %s", elapsed)
+		}
+	})
+
+	t.Run("no retry past the deadline", func(t *testing.T) {
+		srv := &flakyServer{failures: 1, code: codes.ResourceExhausted, hint: 500 * time.Millisecond}
+		client := newRetryTestClient(t, srv, Config{RetryBackoff: ptr("1ms"), RetryMaxBackoff: ptr("1s")})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		if _, err := client.Check(ctx, testCheckRequest()); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("Expected the ResourceExhausted error, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Fatalf("Expected to give up at once, took %s", elapsed)
+		}
+		if got := srv.calls.Load(); got != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", got)
+		}
+	})
+
+	t.Run("other services are not retried", func(t *testing.T) {
+		p := &retryPolicy{maxRetries: 3, backoff: time.Millisecond, maxBackoff: time.Millisecond}
+		var calls int
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			calls++
+			return status.Error(codes.Unavailable, "not yet")
+		}
+
+		err := p.unaryInterceptor(context.Background(), grpc_health_v1.Health_Check_FullMethodName, nil, nil, nil, invoker)
+		if status.Code(err) != codes.Unavailable || calls != 1 {
+			t.Fatalf("Expected a single health Check attempt, got %d (%v)", calls, err)
+		}
+	})
+
+	t.Run("streams are retried before the first message", func(t *testing.T) {
+		srv := &flakyServer{failures: 2, code: codes.Unavailable}
+		client := newRetryTestClient(t, srv, Config{AuthorizationModelId: ptr("model")})
+
+		objects, err := listFirstObject(t, client)
+		if err != nil || !slices.Equal(objects, []string{"0"}) {
+			t.Fatalf("listObjects = (%v, %v), want [0]", objects, err)
+		}
+		if !slices.Equal(srv.users, []string{"user:anne", "user:anne", "user:anne"}) {
+			t.Fatalf("Expected the request on each of 3 attempts, got %v", srv.users)
+		}
+	})
+
+	t.Run("streams are not retried after the first message", func(t *testing.T) {
+		srv := &flakyServer{code: codes.Unavailable, breakMidStream: true}
+		client := newRetryTestClient(t, srv, Config{})
+
+		stream, err := client.StreamedListObjects(context.Background(), &openfgav1.StreamedListObjectsRequest{StoreId: "store", User: "user:anne"})
+		if err != nil {
+			t.Fatalf("StreamedListObjects failed: %v", err)
+		}
+		if _, err := stream.Recv(); err != nil {
+			t.Fatalf("first Recv failed: %v", err)
+		}
+		if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Expected Unavailable, got %v", err)
+		}
+		if got := srv.calls.Load(); got != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", got)
+		}
+	})
+
+	t.Run("stream method timeout", func(t *testing.T) {
+		srv := &flakyServer{delay: 5 * time.Second}
+		client := newRetryTestClient(t, srv, Config{
+			AuthorizationModelId: ptr("model"),
+			MethodTimeouts:       map[string]string{"StreamedListObjects": "50ms"},
+		})
+
+		start := time.Now()
+		if _, err := listFirstObject(t, client); status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("Expected DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Expected the stream to end after the 50ms timeout, took %s", elapsed)
+		}
+		if got := srv.calls.Load(); got != 1 {
+			t.Fatalf("Timeouts should not be retried, got %d attempts", got)
+		}
+	})
+
+	t.Run("stream timeout ends at the first message", func(t *testing.T) {
+		srv := &flakyServer{interval: 200 * time.Millisecond}
+		client := newRetryTestClient(t, srv, Config{MethodTimeouts: map[string]string{"StreamedListObjects": "50ms"}})
+
+		stream, err := client.StreamedListObjects(context.Background(), &openfgav1.StreamedListObjectsRequest{StoreId: "store", User: "user:anne"})
+		if err != nil {
+			t.Fatalf("StreamedListObjects failed: %v", err)
+		}
+		var objects []string
+		for {
+			res, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Recv after %v failed: %v", objects, err)
+			}
+			objects = append(objects, res.GetObject())
+		}
+		if !slices.Equal(objects, []string{"doc:0", "doc:1"}) {
+			t.Fatalf("Expected both objects, got %v", objects)
+		}
+	})
+
+	t.Run("method timeout", func(t *testing.T) {
+		srv := &flakyServer{delay: 5 * time.Second}
+		client := newRetryTestClient(t, srv, Config{MethodTimeouts: map[string]string{"ListObjects": "50ms"}})
+
+		start := time.Now()
+		_, err := client.ListObjects(context.Background(), &openfgav1.ListObjectsRequest{StoreId: "store"})
+		if status.Code(err) != codes.DeadlineExceeded {
+			t.Fatalf("Expected DeadlineExceeded, got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("Expected the call to end after the 50ms timeout, took %s", elapsed)
+		}
+		if got := srv.calls.Load(); got != 1 {
+			t.Fatalf("Timeouts should not be retried, got %d attempts", got)
+		}
+	})
+}
+
+func TestNewRetryPolicy(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		p, err := newRetryPolicy(Config{})
+		if err != nil {
+			t.Fatalf("newRetryPolicy failed: %v", err)
+		}
+		if p.maxRetries != defaultMaxRetries || p.timeoutFor(openfgav1.OpenFGAService_Check_FullMethodName) != defaultRequestTimeout {
+			t.Fatalf("Unexpected defaults: %+v", p)
+		}
+	})
+
+	t.Run("method timeouts override request_timeout", func(t *testing.T) {
+		p, err := newRetryPolicy(Config{RequestTimeout: ptr("10s"), MethodTimeouts: map[string]string{"ListObjects": "1m", "Check": "0s", "StreamedListObjects": "2m"}})
+		if err != nil {
+			t.Fatalf("newRetryPolicy failed: %v", err)
+		}
+		if p.timeoutFor(openfgav1.OpenFGAService_Read_FullMethodName) != 10*time.Second ||
+			p.timeoutFor(openfgav1.OpenFGAService_ListObjects_FullMethodName) != time.Minute ||
+			p.timeoutFor(openfgav1.OpenFGAService_Check_FullMethodName) != 0 ||
+			p.timeoutFor(openfgav1.OpenFGAService_StreamedListObjects_FullMethodName) != 2*time.Minute {
+			t.Fatalf("Unexpected timeouts: %+v", p.methodTimeouts)
+		}
+		// 다른 서비스의 같은 이름 RPC 에는 적용되지 않는다.
+		if p.timeoutFor(grpc_health_v1.Health_Check_FullMethodName) != 10*time.Second {
+			t.Fatalf("method_timeouts.Check must not apply to the health Check")
+		}
+	})
+
+	invalid := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"negative retries", Config{MaxRetries: ptr(-1)}, "max_retries"},
+		{"bad duration", Config{RequestTimeout: ptr("30")}, "request_timeout"},
+		{"backoff above max", Config{RetryBackoff: ptr("10s"), RetryMaxBackoff: ptr("1s")}, "retry_backoff"},
+		{"unknown method", Config{MethodTimeouts: map[string]string{"list_objects": "1s"}}, "unknown OpenFGA RPC"},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newRetryPolicy(tt.cfg); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("newRetryPolicy = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBackoffFor(t *testing.T) {
+	p := &retryPolicy{backoff: 100 * time.Millisecond, maxBackoff: time.Second}
+	for attempt, ceiling := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		for range 20 {
+			if got := p.backoffFor(attempt); got <= 0 || got > ceiling {
+				t.Fatalf("backoffFor(%d) = %s, want within (0, %s]", attempt, got, ceiling)
+			}
+		}
+	}
+}
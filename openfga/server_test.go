@@ -0,0 +1,39 @@
+package openfga
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeOpenFGAServer is an in-process OpenFGA service for client tests.
+// Check allows every tuple; other RPCs are unimplemented unless a test overrides them.
+type fakeOpenFGAServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+}
+
+func (s *fakeOpenFGAServer) Check(_ context.Context, _ *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	return &openfgav1.CheckResponse{Allowed: true}, nil
+}
+
+// startTestServer serves srv on a loopback port until the test ends and returns its address.
+func startTestServer(t *testing.T, srv openfgav1.OpenFGAServiceServer, opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	server := grpc.NewServer(opts...)
+	openfgav1.RegisterOpenFGAServiceServer(server, srv)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
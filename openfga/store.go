@@ -0,0 +1,168 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	openfgainternal "github.com/carped99/steampipe-plugin-openfga/internal/openfga"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+const storeIDCol = "store_id"
+
+// allStores in store_ids selects every active store of the server.
+const allStores = "*"
+
+// activeStoresTTL bounds how long the active stores found for store_ids = ["*"] are reused.
+const activeStoresTTL = 30 * time.Second
+
+// validateStoreConfig checks that at most one of store_id, store_name and store_ids selects the stores.
+func validateStoreConfig(storeID, storeName string, storeIDs []string) error {
+	set := 0
+	for _, ok := range []bool{storeID != "", storeName != "", len(storeIDs) > 0} {
+		if ok {
+			set++
+		}
+	}
+
+	switch {
+	case set > 1:
+		return fmt.Errorf("set only one of store_id, store_name or store_ids")
+	case slices.Contains(storeIDs, allStores) && len(storeIDs) > 1:
+		return fmt.Errorf("store_ids = [\"*\"] selects every active store and cannot be combined with store IDs")
+	case slices.Contains(storeIDs, ""):
+		return fmt.Errorf("store_ids must not contain an empty store ID")
+	}
+	return nil
+}
+
+// resolveStoreName looks up the store_name through ListStores and keeps its ID for the life of the client.
+func (c *Client) resolveStoreName(ctx context.Context) error {
+	if c.storeName == "" || c.storeID != "" {
+		return nil
+	}
+
+	store, err := openfgainternal.FindStoreByName(ctx, c, c.storeName)
+	if err != nil {
+		return fmt.Errorf("store_name: %w", err)
+	}
+	c.storeID = store.GetId()
+	return nil
+}
+
+// withStore returns a copy of the client that sends requests to storeID.
+func (c *Client) withStore(storeID string) *Client {
+	if storeID == c.storeID {
+		return c
+	}
+	scoped := *c
+	scoped.storeID = storeID
+	return &scoped
+}
+
+// configuredStores returns the stores a query fans out to: store_ids, every active store for ["*"], or store_id.
+func (c *Client) configuredStores(ctx context.Context) ([]string, error) {
+	switch {
+	case slices.Equal(c.storeIDs, []string{allStores}):
+		return c.activeStores(ctx)
+	case len(c.storeIDs) > 0:
+		return c.storeIDs, nil
+	case c.storeID != "":
+		return []string{c.storeID}, nil
+	default:
+		return nil, fmt.Errorf("store_id, store_name or store_ids is required")
+	}
+}
+
+// servesStore reports whether the connection's queries fan out to the store.
+func (c *Client) servesStore(id string, deletedAt *time.Time) bool {
+	switch {
+	case slices.Equal(c.storeIDs, []string{allStores}):
+		return deletedAt == nil
+	case len(c.storeIDs) > 0:
+		return slices.Contains(c.storeIDs, id)
+	default:
+		return id == c.storeID
+	}
+}
+
+func (c *Client) activeStores(ctx context.Context) ([]string, error) {
+	c.cache.mu.Lock()
+	cached, at := c.cache.activeStores, c.cache.activeStoresAt
+	c.cache.mu.Unlock()
+	if cached != nil && time.Since(at) < activeStoresTTL {
+		return cached, nil
+	}
+
+	ids, err, _ := c.cache.inflight.Do("stores", func() (any, error) {
+		stores, err := openfgainternal.FindActiveStores(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]string, 0, len(stores))
+		for _, store := range stores {
+			ids = append(ids, store.GetId())
+		}
+		c.cache.mu.Lock()
+		c.cache.activeStores = ids
+		c.cache.activeStoresAt = time.Now()
+		c.cache.mu.Unlock()
+		return ids, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids.([]string), nil
+}
+
+// storeMatrix fans every table out over the connection's stores; the SDK runs the matrix items in parallel.
+// A store_id qual routes the query to the stores it names; getClient rejects those missing from store_ids.
+//
+// The SDK gives the matrix function no way to fail the query. When the stores cannot be resolved, or store_ids = ["*"]
+// finds none, it returns no matrix, so the SDK runs the hydrate once without a store and getClient reports the error.
+func storeMatrix(ctx context.Context, d *plugin.QueryData) []map[string]any {
+	var storeIDs []string
+	for _, q := range d.QueryContext.UnsafeQuals[storeIDCol].GetQuals() {
+		if q.GetStringValue() == quals.QualOperatorEqual {
+			storeIDs = qualValueStrings(q.GetValue())
+			break
+		}
+	}
+
+	if len(storeIDs) == 0 {
+		client, err := getConnectionClient(ctx, d)
+		if err != nil {
+			plugin.Logger(ctx).Error("storeMatrix", "connection_error", err)
+			return nil
+		}
+		if storeIDs, err = client.configuredStores(ctx); err != nil {
+			plugin.Logger(ctx).Error("storeMatrix", "store_discovery_error", err)
+			return nil
+		}
+		if len(storeIDs) == 0 {
+			return nil
+		}
+	}
+
+	matrix := make([]map[string]any, 0, len(storeIDs))
+	for _, id := range storeIDs {
+		matrix = append(matrix, map[string]any{storeIDCol: id})
+	}
+	return matrix
+}
+
+// storeIDColumn is the store_id column every store-scoped table carries.
+func storeIDColumn() *plugin.Column {
+	return &plugin.Column{
+		Name:        storeIDCol,
+		Type:        proto.ColumnType_STRING,
+		Description: "Store the row belongs to; qualify it to query a single store, otherwise every configured store is queried. With store_ids only listed stores can be qualified, with store_id or store_name any store",
+		Transform:   transform.FromMatrixItem(storeIDCol),
+	}
+}
@@ -0,0 +1,278 @@
+package openfga
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// storeListServer serves a fixed set of stores from ListStores, or fails with err, and counts the calls.
+type storeListServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	stores []*openfgav1.Store
+	err    error
+	calls  int
+}
+
+func (s *storeListServer) ListStores(_ context.Context, _ *openfgav1.ListStoresRequest) (*openfgav1.ListStoresResponse, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &openfgav1.ListStoresResponse{Stores: s.stores}, nil
+}
+
+func TestValidateStoreConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		storeID   string
+		storeName string
+		storeIDs  []string
+		wantErr   bool
+	}{
+		{"store_id only", "a", "", nil, false},
+		{"store_name only", "", "prod", nil, false},
+		{"store_ids only", "", "", []string{"a", "b"}, false},
+		{"every store", "", "", []string{"*"}, false},
+		{"none", "", "", nil, false},
+		{"store_id and store_ids", "a", "", []string{"b"}, true},
+		{"store_id and store_name", "a", "prod", nil, true},
+		{"store_name and store_ids", "", "prod", []string{"b"}, true},
+		{"wildcard with ids", "", "", []string{"*", "a"}, true},
+		{"empty id", "", "", []string{"a", ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateStoreConfig(tt.storeID, tt.storeName, tt.storeIDs); (err != nil) != tt.wantErr {
+				t.Fatalf("validateStoreConfig(%q, %q, %v) = %v, wantErr %v", tt.storeID, tt.storeName, tt.storeIDs, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfiguredStores(t *testing.T) {
+	srv := &storeListServer{stores: []*openfgav1.Store{
+		{Id: "a"},
+		{Id: "b", DeletedAt: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+		{Id: "c"},
+	}}
+	addr := startTestServer(t, srv)
+
+	newTestClient := func(t *testing.T, cfg Config) *Client {
+		t.Helper()
+		cfg.Endpoint = addr
+		client, err := NewClient(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+
+	t.Run("store_id", func(t *testing.T) {
+		got, err := newTestClient(t, Config{StoreId: ptr("a")}).configuredStores(context.Background())
+		if err != nil || !slices.Equal(got, []string{"a"}) {
+			t.Fatalf("configuredStores = (%v, %v), want [a]", got, err)
+		}
+	})
+
+	t.Run("store_ids", func(t *testing.T) {
+		got, err := newTestClient(t, Config{StoreIds: []string{"c", "b"}}).configuredStores(context.Background())
+		if err != nil || !slices.Equal(got, []string{"c", "b"}) {
+			t.Fatalf("configuredStores = (%v, %v), want [c b]", got, err)
+		}
+	})
+
+	t.Run("unconfigured", func(t *testing.T) {
+		got, err := newTestClient(t, Config{}).configuredStores(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "store_id, store_name or store_ids is required") {
+			t.Fatalf("configuredStores = (%v, %v), want an error", got, err)
+		}
+	})
+
+	t.Run("every active store is cached", func(t *testing.T) {
+		srv.calls = 0
+		client := newTestClient(t, Config{StoreIds: []string{allStores}})
+		for range 2 {
+			got, err := client.configuredStores(context.Background())
+			if err != nil || !slices.Equal(got, []string{"a", "c"}) {
+				t.Fatalf("configuredStores = (%v, %v), want [a c]", got, err)
+			}
+		}
+		if srv.calls != 1 {
+			t.Fatalf("Expected 1 ListStores call, got %d", srv.calls)
+		}
+	})
+}
+
+func TestServesStore(t *testing.T) {
+	deleted := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		client    Client
+		id        string
+		deletedAt *time.Time
+		want      bool
+	}{
+		{"configured store_id", Client{storeID: "a"}, "a", nil, true},
+		{"other store", Client{storeID: "a"}, "b", nil, false},
+		{"listed in store_ids", Client{storeIDs: []string{"a", "b"}}, "b", nil, true},
+		{"not listed in store_ids", Client{storeIDs: []string{"a", "b"}}, "c", nil, false},
+		{"active store for *", Client{storeIDs: []string{allStores}}, "c", nil, true},
+		{"deleted store for *", Client{storeIDs: []string{allStores}}, "c", &deleted, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.client.servesStore(tt.id, tt.deletedAt); got != tt.want {
+				t.Fatalf("servesStore(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithStore(t *testing.T) {
+	client := &Client{storeID: "a", cache: &resolveCache{latestModels: map[string]cachedModel{}}}
+
+	if got := client.withStore("a"); got != client {
+		t.Fatalf("withStore of the current store should return the client itself")
+	}
+
+	scoped := client.withStore("b")
+	if scoped.storeID != "b" || client.storeID != "a" {
+		t.Fatalf("withStore(b) = %q, original %q", scoped.storeID, client.storeID)
+	}
+	if scoped.cache != client.cache {
+		t.Fatalf("withStore should share the resolve cache")
+	}
+}
+
+func TestResolveStoreName(t *testing.T) {
+	deleted := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	srv := &storeListServer{stores: []*openfgav1.Store{
+		{Id: "a", Name: "prod"},
+		{Id: "b", Name: "staging", DeletedAt: deleted},
+		{Id: "c", Name: "staging"},
+		{Id: "d", Name: "dev"},
+		{Id: "e", Name: "dev"},
+	}}
+	addr := startTestServer(t, srv)
+
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{name: "prod", want: "a"},
+		{name: "staging", want: "c"},
+		{name: "qa", wantErr: "no active OpenFGA store"},
+		{name: "dev", wantErr: "2 active OpenFGA stores"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(context.Background(), Config{Endpoint: addr, StoreName: ptr(tt.name)})
+			if err != nil {
+				t.Fatalf("NewClient failed: %v", err)
+			}
+			defer client.Close()
+
+			err = client.resolveStoreName(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("resolveStoreName(%q) = %v, want error containing %q", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || client.storeID != tt.want {
+				t.Fatalf("resolveStoreName(%q) = (%q, %v), want %q", tt.name, client.storeID, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStoreMatrix_Unresolved(t *testing.T) {
+	addr := startTestServer(t, &storeListServer{err: status.Error(codes.Unavailable, "stores are down")})
+	emptyAddr := startTestServer(t, &storeListServer{})
+	t.Cleanup(clearClientCache)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"unconfigured connection", Config{Endpoint: addr}, "store_id, store_name or store_ids is required"},
+		{"store discovery fails", Config{Endpoint: addr, StoreIds: []string{allStores}, MaxRetries: ptr(0)}, "stores are down"},
+		{"no active store for *", Config{Endpoint: emptyAddr, StoreIds: []string{allStores}}, `store_ids = ["*"] found no active store`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := testContext()
+			d := &plugin.QueryData{
+				Connection:   &plugin.Connection{Name: tt.name, Config: &tt.cfg},
+				EqualsQuals:  plugin.KeyColumnEqualsQualMap{},
+				QueryContext: &plugin.QueryContext{},
+			}
+
+			// no matrix: the SDK runs the hydrate once, without a store
+			if matrix := storeMatrix(ctx, d); matrix != nil {
+				t.Fatalf("storeMatrix = %v, want no matrix", matrix)
+			}
+			if _, err := getClient(ctx, d); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("getClient error = %v, want %q", err, tt.wantErr)
+			}
+			// openfga_store is not scoped to a store and keeps working
+			if _, err := getConnectionClient(ctx, d); err != nil {
+				t.Fatalf("getConnectionClient failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetClient_QualifiedStore(t *testing.T) {
+	t.Cleanup(clearClientCache)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		storeID string
+		wantErr bool
+	}{
+		{"listed in store_ids", Config{StoreIds: []string{"a", "b"}}, "b", false},
+		{"not listed in store_ids", Config{StoreIds: []string{"a", "b"}}, "c", true},
+		{"any store for *", Config{StoreIds: []string{allStores}}, "c", false},
+		{"any store for store_id", Config{StoreId: ptr("a")}, "c", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Endpoint = "127.0.0.1:1"
+			d := &plugin.QueryData{
+				Connection:  &plugin.Connection{Name: tt.name, Config: &tt.cfg},
+				EqualsQuals: plugin.KeyColumnEqualsQualMap{storeIDCol: stringQual(tt.storeID)},
+			}
+
+			client, err := getClient(testContext(), d)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "not in store_ids") {
+					t.Fatalf("getClient = (%v, %v), want the store rejected", client, err)
+				}
+				return
+			}
+			if err != nil || client.storeID != tt.storeID {
+				t.Fatalf("getClient = (%v, %v), want store %s", client, err, tt.storeID)
+			}
+		})
+	}
+}
@@ -2,30 +2,37 @@ package openfga
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"strconv"
 	"time"
 
 	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
-// (object_type, object_id, subject_type, subject_id, relation)
-//	→ Check 1회
+// relation 은 항상 필수이고, 주어진 qual 조합에 따라 호출하는 API 와 행의 의미가 달라진다 (source 컬럼).
 //
-//(object_type, subject_type, subject_id, relation)
-//→ “이 subject 가 이 relation 으로 접근 가능한 object_type 모든 object”
-//→ ListObjects
+// 평가된 권한 (source = 'evaluated'): 모델의 rewrite, contextual_tuples, context 를 반영한 결과
 //
-//(object_type, object_id, relation)
-//→ “이 object 에 이 relation 을 가진 모든 subject”
-//→ ListUsers
+//	(object_type, object_id, subject_type, subject_id) → Check, 여러 값이면 BatchCheck
 //
-//(subject_type, subject_id, relation) (object_type 없이)
-//→ “이 subject 가 이 relation 을 가진 모든 object (모든 type)”
-//→ Read (user+relation filter) + 클라이언트에서 type:id 파싱
+//	  BatchCheck 로 묶이는 것은 IN (...) / = ANY(array) 로 적은 목록뿐이다. 다른 테이블과 조인하면 Postgres 가
+//	  바깥 행마다 = qual 하나로 스캔하므로 행마다 Check 를 한 번씩 보낸다.
+//
+//	(object_type, subject_type, subject_id)            → ListObjects: subject 가 접근 가능한 object_type 의 모든 object
+//	(object_type, object_id, subject_type)             → ListUsers: object 에 접근 가능한 subject_type 의 모든 subject
+//
+// 저장된 tuple (source = 'stored'): 그 외 조합, 예) (object_type, object_id), (subject_type, subject_id), (object_type)
+//
+//	→ Read 결과 그대로. rewrite 로 유도된 권한은 포함하지 않으며 allowed 는 항상 true 이다.
+//	  Read 가 받을 수 있는 필터 (object "type:id", user 와 함께일 때만 "type:", relation, subject_relation 까지 정해진 user)
+//	  만 보내고 나머지는 클라이언트에서 거른다. 조합은 tupleFilter.readTupleKey 참고.
 
 type AclPermissionRow struct {
 	ObjectType  string    `json:"object_type"`
@@ -33,7 +40,62 @@ type AclPermissionRow struct {
 	SubjectType string    `json:"subject_type"`
 	SubjectID   string    `json:"subject_id"`
 	Relation    string    `json:"relation"`
+	Allowed     bool      `json:"allowed"`
+	Error       string    `json:"error"`
 	EvaluatedAt time.Time `json:"evaluated_at"`
+
+	SubjectRelation string `json:"subject_relation"`
+	SubjectKind     string `json:"subject_kind"`
+
+	AuthorizationModelID string          `json:"authorization_model_id"`
+	PolicyVersion        string          `json:"policy_version"`
+	ContextualTuples     json.RawMessage `json:"contextual_tuples"`
+	Context              json.RawMessage `json:"context"`
+	Consistency          string          `json:"consistency"`
+	Source               string          `json:"source"`
+}
+
+// checkTuple is a single (object, relation, subject) combination to evaluate.
+type checkTuple struct {
+	ObjectType      string
+	ObjectID        string
+	SubjectType     string
+	SubjectID       string
+	SubjectRelation string
+	Relation        string
+}
+
+func (t checkTuple) tupleKey() *openfgav1.CheckRequestTupleKey {
+	return &openfgav1.CheckRequestTupleKey{
+		Object:   t.ObjectType + ":" + t.ObjectID,
+		User:     joinUser(t.SubjectType, t.SubjectID, t.SubjectRelation),
+		Relation: t.Relation,
+	}
+}
+
+// Row sources reported in the source column.
+const (
+	sourceEvaluated = "evaluated"
+	sourceStored    = "stored"
+)
+
+// Subject kinds reported in the subject_kind column.
+const (
+	subjectKindObject   = "object"
+	subjectKindUserset  = "userset"
+	subjectKindWildcard = "wildcard"
+)
+
+// subjectKind classifies a subject: "group:eng#member" is a userset, "user:*" a wildcard and "user:anne" an object.
+func subjectKind(subjectID, subjectRelation string) string {
+	switch {
+	case subjectRelation != "":
+		return subjectKindUserset
+	case subjectID == "*":
+		return subjectKindWildcard
+	default:
+		return subjectKindObject
+	}
 }
 
 var (
@@ -41,37 +103,59 @@ var (
 	objectIDCol    = "object_id"
 	subjectTypeCol = "subject_type"
 	subjectIDCol   = "subject_id"
+	subjectRelCol  = "subject_relation"
 	relationCol    = "relation"
+	allowedCol     = "allowed"
+	errorCol       = "error"
+
+	authorizationModelIDCol = "authorization_model_id"
+	contextualTuplesCol     = "contextual_tuples"
+	contextCol              = "context"
+	consistencyCol          = "consistency"
 )
 
+// maxChecksPerBatch matches the OpenFGA server default for OPENFGA_MAX_CHECKS_PER_BATCH_CHECK.
+const maxChecksPerBatch = 50
+
 func tableAclPermission(_ context.Context) *plugin.Table {
 	return &plugin.Table{
-		Name:        "sys_acl_permission",
-		Description: "Real-time permission check via OpenFGA Check API",
+		Name:              "sys_acl_permission",
+		Description:       "Real-time permission check via OpenFGA Check API",
+		GetMatrixItemFunc: storeMatrix,
 		List: &plugin.ListConfig{
 			Hydrate: listPermission,
 			KeyColumns: []*plugin.KeyColumn{
 				{Name: relationCol, Require: plugin.Required},
+				{Name: objectTypeCol, Require: plugin.Optional},
+				{Name: objectIDCol, Require: plugin.Optional},
+				{Name: subjectTypeCol, Require: plugin.Optional},
+				{Name: subjectIDCol, Require: plugin.Optional},
+				{Name: subjectRelCol, Require: plugin.Optional},
+				{Name: authorizationModelIDCol, Require: plugin.Optional},
+				{Name: contextualTuplesCol, Require: plugin.Optional},
+				{Name: contextCol, Require: plugin.Optional},
+				{Name: consistencyCol, Require: plugin.Optional},
 			},
 		},
-		Get: &plugin.GetConfig{
-			Hydrate: getPermission,
-			KeyColumns: plugin.AllColumns([]string{
-				objectTypeCol,
-				objectIDCol,
-				subjectTypeCol,
-				subjectIDCol,
-				relationCol,
-			}),
-		},
 		Columns: []*plugin.Column{
 			{Name: objectTypeCol, Type: proto.ColumnType_STRING, Description: "Logical type of the protected object"},
-			{Name: objectIDCol, Type: proto.ColumnType_STRING, Description: "Application-level identifier of the object"},
+			{Name: objectIDCol, Type: proto.ColumnType_STRING, Description: "Application-level identifier of the object. A literal IN (...) or = ANY(array) list is evaluated in one BatchCheck; a join is not batched and sends one Check per joined row"},
 			{Name: subjectTypeCol, Type: proto.ColumnType_STRING, Description: "Type of the subject (e.g. 'user', 'group', 'service')"},
-			{Name: subjectIDCol, Type: proto.ColumnType_STRING, Description: "Identifier of the subject (user ID, group ID etc)"},
+			{Name: subjectIDCol, Type: proto.ColumnType_STRING, Description: "Identifier of the subject (user ID, group ID etc), '*' for a wildcard. A literal IN (...) or = ANY(array) list is evaluated in one BatchCheck; a join is not batched and sends one Check per joined row"},
+			{Name: subjectRelCol, Type: proto.ColumnType_STRING, Description: "Relation of a userset subject, e.g. 'member' for 'group:eng#member'"},
+			{Name: "subject_kind", Type: proto.ColumnType_STRING, Description: "Kind of subject: object, userset or wildcard"},
 			{Name: relationCol, Type: proto.ColumnType_STRING, Description: "Relation to check(e.g. 'reader', 'writer')"},
+			// NullIfZero 를 적용하면 false 가 NULL 로 바뀌므로 기본 transform 을 사용하지 않는다.
+			{Name: allowedCol, Type: proto.ColumnType_BOOL, Description: "Whether OpenFGA allows the subject the relation on the object", Transform: transform.FromField("Allowed")},
+			{Name: errorCol, Type: proto.ColumnType_STRING, Description: "Error reported by OpenFGA for this tuple in a batch check"},
+			{Name: authorizationModelIDCol, Type: proto.ColumnType_STRING, Description: "Authorization model requested for evaluation, from the qual or the connection's authorization_model_id"},
 			{Name: "policy_version", Type: proto.ColumnType_STRING, Description: "Authorization model or snapshot version used to evaluate this permission."},
 			{Name: "evaluated_at", Type: proto.ColumnType_TIMESTAMP, Description: "Timestamp when this effective permission was evaluated"},
+			{Name: contextualTuplesCol, Type: proto.ColumnType_JSON, Description: "Contextual tuples sent with every evaluation, e.g. [{\"user\": \"user:anne\", \"relation\": \"member\", \"object\": \"org:acme\"}]"},
+			{Name: contextCol, Type: proto.ColumnType_JSON, Description: "Condition context sent with every evaluation, e.g. {\"current_time\": \"2024-01-01T00:00:00Z\"}"},
+			{Name: "source", Type: proto.ColumnType_STRING, Description: "evaluated for rows from Check, BatchCheck, ListObjects or ListUsers; stored for tuples returned by Read when the quals do not name both an object and a subject type"},
+			{Name: consistencyCol, Type: proto.ColumnType_STRING, Description: "Consistency preference of the requests: minimize_latency, higher_consistency or unspecified; defaults to the connection's consistency"},
+			storeIDColumn(),
 		},
 	}
 }
@@ -83,65 +167,124 @@ func listPermission(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateD
 		logger.Debug("listPermission called", "quals", d.EqualsQuals)
 	}
 
-	objectType := d.EqualsQualString(objectTypeCol)
-	objectId := d.EqualsQualString(objectIDCol)
-	subjectType := d.EqualsQualString(subjectTypeCol)
-	subjectId := d.EqualsQualString(subjectIDCol)
-	relation := d.EqualsQualString(relationCol)
+	objectType, err := singleQualString(d, objectTypeCol)
+	if err != nil {
+		return nil, err
+	}
+	subjectType, err := singleQualString(d, subjectTypeCol)
+	if err != nil {
+		return nil, err
+	}
+	subjectRelation, err := singleQualString(d, subjectRelCol)
+	if err != nil {
+		return nil, err
+	}
+	relation, err := singleQualString(d, relationCol)
+	if err != nil {
+		return nil, err
+	}
 
 	if relation == "" {
 		return nil, fmt.Errorf("relation is required")
 	}
 
-	hasObject := objectType != "" && objectId != ""
-	hasSubject := subjectType != "" && subjectId != ""
+	// object_id, subject_id 는 IN / = ANY(...) 목록을 허용한다.
+	// The SDK splits a single list qual into one List call per list element. listQualStrings makes the call
+	// holding the first element handle the whole list, e.g. in one BatchCheck, and the other calls, duplicates
+	// and empty strings included, return without a request.
+	// This stays correct under a LIMIT: the calls share the query's row count, so the leader stops once it is
+	// reached, and every row it streams matches the original IN qual.
+	// With the store_id matrix, each store gets its own leader.
+	objectIds, skip := listQualStrings(d, objectIDCol)
+	if skip {
+		return nil, nil
+	}
+	subjectIds, skip := listQualStrings(d, subjectIDCol)
+	if skip {
+		return nil, nil
+	}
+
+	hasObject := objectType != "" && len(objectIds) > 0
+	hasSubject := subjectType != "" && len(subjectIds) > 0
 	switch {
-	// 1) 단건 조회 - 모든 정보가 있을 때
+	// 1) 단건/다건 조회 - 모든 정보가 있을 때
 	case hasObject && hasSubject:
-		return nil, errors.New("for single permission check, please use the 'get' call instead of 'list'")
+		var tuples []checkTuple
+		for _, objectId := range objectIds {
+			for _, subjectId := range subjectIds {
+				tuples = append(tuples, checkTuple{
+					ObjectType:      objectType,
+					ObjectID:        objectId,
+					SubjectType:     subjectType,
+					SubjectID:       subjectId,
+					SubjectRelation: subjectRelation,
+					Relation:        relation,
+				})
+			}
+		}
+		return checkPermissions(ctx, d, tuples)
 	// 2) subject 있음, object_id 값이 없음
 	case hasSubject && objectType != "":
-		return listObjects(ctx, d, objectType, subjectType, subjectId, relation)
+		for _, subjectId := range subjectIds {
+			if _, err := listObjects(ctx, d, objectType, subjectType, subjectId, subjectRelation, relation); err != nil {
+				return nil, err
+			}
+			if d.RowsRemaining(ctx) == 0 {
+				break
+			}
+		}
+		return nil, nil
 	// 3) object 있음, subject_id 값이 없음
 	case hasObject && subjectType != "":
-		return listUsers(ctx, d, objectType, objectId, subjectType, relation)
+		for _, objectId := range objectIds {
+			if _, err := listUsers(ctx, d, objectType, objectId, subjectType, subjectRelation, relation); err != nil {
+				return nil, err
+			}
+			if d.RowsRemaining(ctx) == 0 {
+				break
+			}
+		}
+		return nil, nil
 	default:
-		return listByRead(ctx, d, objectType, objectId, subjectType, subjectId, relation)
+		if len(objectIds) > 1 || len(subjectIds) > 1 {
+			return nil, fmt.Errorf("list values for %s and %s require both %s and %s", objectIDCol, subjectIDCol, objectTypeCol, subjectTypeCol)
+		}
+		return listByRead(ctx, d, objectType, firstOrEmpty(objectIds), subjectType, firstOrEmpty(subjectIds), subjectRelation, relation)
 	}
 }
 
-func getPermission(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
-	logger := plugin.Logger(ctx)
-	if logger.IsDebug() {
-		logger.Debug("getPermission called", "quals", d.EqualsQuals)
+func listObjects(ctx context.Context, d *plugin.QueryData, objectType, subjectType, subjectID, subjectRelation, relation string) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
 	}
 
-	objectType := d.EqualsQualString(objectTypeCol)
-	objectId := d.EqualsQualString(objectIDCol)
-	subjectType := d.EqualsQualString(subjectTypeCol)
-	subjectId := d.EqualsQualString(subjectIDCol)
-	relation := d.EqualsQualString(relationCol)
-	return check(ctx, d, objectType, objectId, subjectType, subjectId, relation)
-}
-
-func listObjects(ctx context.Context, d *plugin.QueryData, objectType, subjectType, subjectID, relation string) (any, error) {
-	client, err := getClient(ctx, d)
+	opts, err := newEvaluationOptions(ctx, d, client)
 	if err != nil {
 		return nil, err
 	}
 
 	req := &openfgav1.StreamedListObjectsRequest{
-		StoreId:  client.storeID,
-		Relation: relation,
-		User:     subjectType + ":" + subjectID,
-		Type:     objectType,
+		StoreId:              client.storeID,
+		AuthorizationModelId: opts.policyVersion,
+		Relation:             relation,
+		User:                 joinUser(subjectType, subjectID, subjectRelation),
+		Type:                 objectType,
+		ContextualTuples:     opts.contextualTupleKeys(),
+		Context:              opts.context,
+		Consistency:          opts.consistency,
 	}
 
+	// LIMIT 로 일찍 끝나도 스트림이 닫히도록 취소한다.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	res, err := client.StreamedListObjects(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("ListObjects: %w", err)
 	}
 
+	evaluatedAt := time.Now().UTC()
 	for {
 		chunk, err := res.Recv()
 		if errors.Is(err, io.EOF) {
@@ -159,6 +302,18 @@ func listObjects(ctx context.Context, d *plugin.QueryData, objectType, subjectTy
 			SubjectType: subjectType,
 			SubjectID:   subjectID,
 			Relation:    relation,
+			Allowed:     true,
+			EvaluatedAt: evaluatedAt,
+
+			SubjectRelation: subjectRelation,
+			SubjectKind:     subjectKind(subjectID, subjectRelation),
+
+			AuthorizationModelID: opts.modelID,
+			PolicyVersion:        opts.policyVersion,
+			ContextualTuples:     opts.contextualTuplesJSON,
+			Context:              opts.contextJSON,
+			Consistency:          opts.consistencyText,
+			Source:               sourceEvaluated,
 		}
 		d.StreamListItem(ctx, row)
 
@@ -170,25 +325,36 @@ func listObjects(ctx context.Context, d *plugin.QueryData, objectType, subjectTy
 	return nil, nil
 }
 
-func listUsers(ctx context.Context, d *plugin.QueryData, objectType, objectID, subjectType, relation string) (any, error) {
+// listUsers lists the subjects of subjectType related to the object. With subjectRelation it lists usersets such as
+// group#member instead; OpenFGA then only returns usersets of that relation.
+func listUsers(ctx context.Context, d *plugin.QueryData, objectType, objectID, subjectType, subjectRelation, relation string) (any, error) {
 	client, err := getClient(ctx, d)
 	if err != nil {
 		return nil, err
 	}
 
+	opts, err := newEvaluationOptions(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+
 	req := &openfgav1.ListUsersRequest{
-		StoreId:  client.storeID,
-		Relation: relation,
+		StoreId:              client.storeID,
+		AuthorizationModelId: opts.policyVersion,
+		Relation:             relation,
 		Object: &openfgav1.Object{
 			Type: objectType,
 			Id:   objectID,
 		},
 		UserFilters: []*openfgav1.UserTypeFilter{
 			{
-				Type: subjectType,
+				Type:     subjectType,
+				Relation: subjectRelation,
 			},
 		},
-		Consistency: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY,
+		ContextualTuples: opts.contextualTuples,
+		Context:          opts.context,
+		Consistency:      opts.consistency,
 	}
 
 	res, err := client.ListUsers(ctx, req)
@@ -198,182 +364,349 @@ func listUsers(ctx context.Context, d *plugin.QueryData, objectType, objectID, s
 
 	evaluatedAt := time.Now().UTC()
 	for _, user := range res.GetUsers() {
+		row := AclPermissionRow{
+			ObjectType:  objectType,
+			ObjectID:    objectID,
+			Relation:    relation,
+			Allowed:     true,
+			EvaluatedAt: evaluatedAt,
+
+			AuthorizationModelID: opts.modelID,
+			PolicyVersion:        opts.policyVersion,
+			ContextualTuples:     opts.contextualTuplesJSON,
+			Context:              opts.contextJSON,
+			Consistency:          opts.consistencyText,
+			Source:               sourceEvaluated,
+		}
+
 		switch v := user.GetUser().(type) {
 		case *openfgav1.User_Object:
-			obj := v.Object
-			if obj == nil {
-				continue
-			}
-			row := AclPermissionRow{
-				ObjectType:  objectType,
-				ObjectID:    objectID,
-				SubjectType: obj.Type,
-				SubjectID:   obj.Id,
-				Relation:    relation,
-				EvaluatedAt: evaluatedAt,
-			}
-			d.StreamListItem(ctx, row)
-
+			row.SubjectType = v.Object.GetType()
+			row.SubjectID = v.Object.GetId()
 		case *openfgav1.User_Userset:
-			return nil, fmt.Errorf("userset subjects are not supported in ListUsers results")
-			//us := v.Userset
-			//if us == nil {
-			//	continue
-			//}
-			//out = append(out, ACLSubject{
-			//	Kind:        SubjectKindUserset,
-			//	SubjectType: us.GetType(),     // "group"
-			//	SubjectID:   us.GetId(),       // "eng"
-			//	UsersetRel:  us.GetRelation(), // "member"
-			//})
-
+			// "group:eng#member"
+			row.SubjectType = v.Userset.GetType()
+			row.SubjectID = v.Userset.GetId()
+			row.SubjectRelation = v.Userset.GetRelation()
 		case *openfgav1.User_Wildcard:
-			return nil, fmt.Errorf("wildcard subjects are not supported in ListUsers results")
-			//w := v.Wildcard
-			//if w == nil {
-			//	continue
-			//}
-			//out = append(out, ACLSubject{
-			//	Kind:        SubjectKindWildcard,
-			//	SubjectType: w.GetType(), // "user"
-			//	SubjectID:   "*",         // 관례적으로, 전체
-			//})
+			// "user:*"
+			row.SubjectType = v.Wildcard.GetType()
+			row.SubjectID = "*"
+		default:
+			continue
+		}
+		row.SubjectKind = subjectKind(row.SubjectID, row.SubjectRelation)
+
+		d.StreamListItem(ctx, row)
+		if d.RowsRemaining(ctx) == 0 {
+			break
 		}
 	}
 	return nil, nil
 }
 
-func check(ctx context.Context, d *plugin.QueryData, objectType, objectId, subjectType, subjectId, relation string) (any, error) {
-	logger := plugin.Logger(ctx)
-	if logger.IsDebug() {
-		logger.Debug("check called", "quals", d.EqualsQuals)
+// requestOptions holds the per-query settings applied to every OpenFGA request the table issues.
+type requestOptions struct {
+	// modelID is the authorization_model_id qual, else the model pinned in the connection config.
+	modelID string
+	// policyVersion is the model that evaluates the request: modelID, or the store's latest model.
+	// It is only resolved for evaluated requests, see newEvaluationOptions.
+	policyVersion string
+	// contextualTuples are sent with every evaluated request; contextualTuplesJSON is the qual as written, echoed on rows.
+	contextualTuples     []*openfgav1.TupleKey
+	contextualTuplesJSON json.RawMessage
+	// context is the condition context sent with every evaluated request; contextJSON is the qual as written.
+	context     *structpb.Struct
+	contextJSON json.RawMessage
+	// consistency is the consistency qual, else the connection default; consistencyText is what rows echo.
+	consistency     openfgav1.ConsistencyPreference
+	consistencyText string
+}
+
+// contextualTupleKeys wraps contextualTuples for the requests that take ContextualTupleKeys.
+func (o requestOptions) contextualTupleKeys() *openfgav1.ContextualTupleKeys {
+	if len(o.contextualTuples) == 0 {
+		return nil
 	}
+	return &openfgav1.ContextualTupleKeys{TupleKeys: o.contextualTuples}
+}
 
-	client, err := getClient(ctx, d)
+func newRequestOptions(d *plugin.QueryData, client *Client) (requestOptions, error) {
+	modelID, err := singleQualString(d, authorizationModelIDCol)
 	if err != nil {
-		return nil, err
+		return requestOptions{}, err
+	}
+	if modelID == "" {
+		modelID = client.modelID
 	}
 
-	req := &openfgav1.CheckRequest{
-		StoreId: client.storeID,
-		TupleKey: &openfgav1.CheckRequestTupleKey{
-			Object:   objectType + ":" + objectId,
-			User:     subjectType + ":" + subjectId,
-			Relation: relation,
-		},
-		Consistency: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY,
+	contextualTuplesJSON := jsonbQual(d, contextualTuplesCol)
+	contextualTuples, err := parseContextualTuples(contextualTuplesJSON)
+	if err != nil {
+		return requestOptions{}, err
 	}
 
-	res, err := client.Check(ctx, req)
+	contextJSON := jsonbQual(d, contextCol)
+	evalContext, err := parseContext(contextJSON)
 	if err != nil {
-		return nil, fmt.Errorf("check: %w", err)
+		return requestOptions{}, err
 	}
 
-	if !res.GetAllowed() {
-		return nil, nil
+	consistency, consistencyText, err := consistencyQual(d, client)
+	if err != nil {
+		return requestOptions{}, err
 	}
 
-	return AclPermissionRow{
-		ObjectType:  objectType,
-		ObjectID:    objectId,
-		SubjectType: subjectType,
-		SubjectID:   subjectId,
-		Relation:    relation,
-		EvaluatedAt: time.Now().UTC(),
+	return requestOptions{
+		modelID:              modelID,
+		contextualTuples:     contextualTuples,
+		contextualTuplesJSON: contextualTuplesJSON,
+		context:              evalContext,
+		contextJSON:          contextJSON,
+		consistency:          consistency,
+		consistencyText:      consistencyText,
 	}, nil
+}
+
+// newEvaluationOptions is newRequestOptions for requests evaluated against a model.
+// The resolved model is sent with the request so policy_version always names the model that produced the row.
+func newEvaluationOptions(ctx context.Context, d *plugin.QueryData, client *Client) (requestOptions, error) {
+	opts, err := newRequestOptions(d, client)
+	if err != nil {
+		return requestOptions{}, err
+	}
 
+	opts.policyVersion, err = client.resolveModelID(ctx, opts.modelID)
+	if err != nil {
+		return requestOptions{}, err
+	}
+	return opts, nil
 }
 
-// listByRead does not evaluate the tuples in the store.
-//  1. tuple_key is optional. If not specified, it will return all tuples in the store.
-//  2. tuple_key.object is mandatory if tuple_key is specified.
-//     It can be a full object (e.g., type:object_id) or type only (e.g., type:)
-//  3. tuple_key.user is mandatory if tuple_key is specified in the case the tuple_key.object is a type only.
-//     If tuple_key.user is specified, it needs to be a full object (e.g., type:user_id).
-func listByRead(ctx context.Context, d *plugin.QueryData, objectType, objectId, subjectType, subjectId, relation string) (any, error) {
+// checkPermissions evaluates tuples with a single Check, or with BatchCheck in chunks when there are several.
+func checkPermissions(ctx context.Context, d *plugin.QueryData, tuples []checkTuple) (any, error) {
+	if len(tuples) == 1 {
+		row, err := check(ctx, d, tuples[0])
+		if err != nil {
+			return nil, err
+		}
+		d.StreamListItem(ctx, row)
+		return nil, nil
+	}
+
 	client, err := getClient(ctx, d)
 	if err != nil {
 		return nil, err
 	}
 
-	var tupleKey *openfgav1.ReadRequestTupleKey
+	opts, err := newEvaluationOptions(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(tuples); start += maxChecksPerBatch {
+		end := min(start+maxChecksPerBatch, len(tuples))
+		chunk := tuples[start:end]
+
+		req := &openfgav1.BatchCheckRequest{
+			StoreId:              client.storeID,
+			AuthorizationModelId: opts.policyVersion,
+			Checks:               batchCheckItems(chunk, opts),
+			Consistency:          opts.consistency,
+		}
 
-	if objectType != "" {
-		tupleKey = &openfgav1.ReadRequestTupleKey{
-			Object: objectType + ":" + objectId,
+		res, err := client.BatchCheck(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("BatchCheck: %w", err)
 		}
 
-		if objectId == "" {
-			// object_id 가 없으면 -> user 필수
-			if subjectType == "" || subjectId == "" {
-				return nil, fmt.Errorf("subject_type and subject_id are required when object_id is not specified")
+		for _, row := range batchCheckRows(chunk, res, opts, time.Now().UTC()) {
+			d.StreamListItem(ctx, row)
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
 			}
-			tupleKey.User = subjectType + ":" + subjectId
 		}
-		tupleKey.Relation = relation
 	}
+	return nil, nil
+}
 
-	var continuationToken string
-	for {
-		if d.RowsRemaining(ctx) == 0 {
-			return nil, nil
-		}
+// batchCheckItems builds BatchCheck items whose correlation ID is the tuple's index within the chunk.
+func batchCheckItems(tuples []checkTuple, opts requestOptions) []*openfgav1.BatchCheckItem {
+	items := make([]*openfgav1.BatchCheckItem, 0, len(tuples))
+	for i, t := range tuples {
+		items = append(items, &openfgav1.BatchCheckItem{
+			TupleKey:         t.tupleKey(),
+			ContextualTuples: opts.contextualTupleKeys(),
+			Context:          opts.context,
+			CorrelationId:    strconv.Itoa(i),
+		})
+	}
+	return items
+}
 
-		req := &openfgav1.ReadRequest{
-			StoreId:           client.storeID,
-			TupleKey:          tupleKey,
-			ContinuationToken: continuationToken,
-			//PageSize:          wrapperspb.Int32(100), // Use default page size
-			Consistency: openfgav1.ConsistencyPreference_HIGHER_CONSISTENCY,
+// batchCheckRows maps BatchCheck results back to tuples in request order.
+// A tuple without a result, or with a per-item error, is reported as not allowed with the error set.
+func batchCheckRows(tuples []checkTuple, res *openfgav1.BatchCheckResponse, opts requestOptions, evaluatedAt time.Time) []AclPermissionRow {
+	rows := make([]AclPermissionRow, 0, len(tuples))
+	for i, t := range tuples {
+		row := AclPermissionRow{
+			ObjectType:  t.ObjectType,
+			ObjectID:    t.ObjectID,
+			SubjectType: t.SubjectType,
+			SubjectID:   t.SubjectID,
+			Relation:    t.Relation,
+			EvaluatedAt: evaluatedAt,
+
+			SubjectRelation: t.SubjectRelation,
+			SubjectKind:     subjectKind(t.SubjectID, t.SubjectRelation),
+
+			AuthorizationModelID: opts.modelID,
+			PolicyVersion:        opts.policyVersion,
+			ContextualTuples:     opts.contextualTuplesJSON,
+			Context:              opts.contextJSON,
+			Consistency:          opts.consistencyText,
+			Source:               sourceEvaluated,
 		}
 
-		res, err := client.Read(ctx, req)
-		if err != nil {
-			return nil, fmt.Errorf("read: %w", err)
+		result, ok := res.GetResult()[strconv.Itoa(i)]
+		switch {
+		case !ok:
+			row.Error = "no result returned for this check"
+		case result.GetError() != nil:
+			row.Error = checkErrorMessage(result.GetError())
+		default:
+			row.Allowed = result.GetAllowed()
 		}
+		rows = append(rows, row)
+	}
+	return rows
+}
 
-		// ReadResponse.Tuples: []*Tuple
-		for _, t := range res.GetTuples() {
-			if err := ctx.Err(); err != nil {
-				return nil, err
-			}
+func checkErrorMessage(e *openfgav1.CheckError) string {
+	var code string
+	switch e.GetCode().(type) {
+	case *openfgav1.CheckError_InputError:
+		code = e.GetInputError().String()
+	case *openfgav1.CheckError_InternalError:
+		code = e.GetInternalError().String()
+	}
 
-			if d.RowsRemaining(ctx) == 0 {
-				return nil, nil
-			}
+	switch {
+	case code == "":
+		return e.GetMessage()
+	case e.GetMessage() == "":
+		return code
+	default:
+		return code + ": " + e.GetMessage()
+	}
+}
 
-			key := t.GetKey()
-			if key == nil {
-				continue
-			}
+// check evaluates a single tuple and always returns a row, so denials are visible as allowed = false.
+func check(ctx context.Context, d *plugin.QueryData, t checkTuple) (AclPermissionRow, error) {
+	logger := plugin.Logger(ctx)
+	if logger.IsDebug() {
+		logger.Debug("check called", "quals", d.EqualsQuals)
+	}
 
-			key.GetObject()
-			key.GetUser()
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return AclPermissionRow{}, err
+	}
 
-			listObjects(ctx, d, objectType, subjectType, subjectId, relation)
+	opts, err := newEvaluationOptions(ctx, d, client)
+	if err != nil {
+		return AclPermissionRow{}, err
+	}
 
-			// key.Object 는 "doc:123" 이런 문자열
-			objType, objID := splitObject(key.GetObject())
+	req := &openfgav1.CheckRequest{
+		StoreId:              client.storeID,
+		AuthorizationModelId: opts.policyVersion,
+		TupleKey:             t.tupleKey(),
+		ContextualTuples:     opts.contextualTupleKeys(),
+		Context:              opts.context,
+		Consistency:          opts.consistency,
+	}
 
-			// key.User 는 "user:alice" 같은 문자열
-			subjType, subjID := splitObject(key.GetUser())
+	res, err := client.Check(ctx, req)
+	if err != nil {
+		return AclPermissionRow{}, fmt.Errorf("check: %w", err)
+	}
 
-			row := AclPermissionRow{
-				ObjectType:  objType,
-				ObjectID:    objID,
-				SubjectType: subjType,
-				SubjectID:   subjID,
-				Relation:    key.GetRelation(),
-				EvaluatedAt: t.Timestamp.AsTime(),
-			}
+	return AclPermissionRow{
+		ObjectType:  t.ObjectType,
+		ObjectID:    t.ObjectID,
+		SubjectType: t.SubjectType,
+		SubjectID:   t.SubjectID,
+		Relation:    t.Relation,
+		Allowed:     res.GetAllowed(),
+		EvaluatedAt: time.Now().UTC(),
 
-			d.StreamListItem(ctx, row)
+		SubjectRelation: t.SubjectRelation,
+		SubjectKind:     subjectKind(t.SubjectID, t.SubjectRelation),
+
+		AuthorizationModelID: opts.modelID,
+		PolicyVersion:        opts.policyVersion,
+		ContextualTuples:     opts.contextualTuplesJSON,
+		Context:              opts.contextJSON,
+		Consistency:          opts.consistencyText,
+		Source:               sourceEvaluated,
+	}, nil
+}
+
+// listByRead streams the stored tuples matching the quals, exactly as Read returns them.
+// Unlike the evaluated paths it does not follow rewrites, so a viewer derived from editor is not listed.
+func listByRead(ctx context.Context, d *plugin.QueryData, objectType, objectId, subjectType, subjectId, subjectRelation, relation string) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := newRequestOptions(d, client)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := tupleFilter{
+		ObjectType:   objectType,
+		ObjectID:     objectId,
+		Relation:     relation,
+		UserType:     subjectType,
+		UserID:       subjectId,
+		UserRelation: subjectRelation,
+	}
+
+	err = visitTuples(ctx, client, filter.readTupleKey(), opts.consistency, func(t *openfgav1.Tuple) bool {
+		key := t.GetKey()
+		if !filter.matches(key) {
+			return true
 		}
 
-		continuationToken = res.GetContinuationToken()
-		if continuationToken == "" {
-			break
+		// key.Object 는 "doc:123", key.User 는 "user:alice", "group:eng#member", "user:*" 같은 문자열
+		objType, objID := splitObject(key.GetObject())
+		subjType, subjID, subjRel := splitUser(key.GetUser())
+
+		row := AclPermissionRow{
+			ObjectType:  objType,
+			ObjectID:    objID,
+			SubjectType: subjType,
+			SubjectID:   subjID,
+			Relation:    key.GetRelation(),
+			Allowed:     true,
+			EvaluatedAt: t.GetTimestamp().AsTime(),
+
+			SubjectRelation: subjRel,
+			SubjectKind:     subjectKind(subjID, subjRel),
+
+			// Read 는 모델, contextual tuple, context 와 무관하지만 qual 이 행을 걸러내지 않도록 그대로 돌려준다.
+			AuthorizationModelID: opts.modelID,
+			ContextualTuples:     opts.contextualTuplesJSON,
+			Context:              opts.contextJSON,
+			Consistency:          opts.consistencyText,
+			Source:               sourceStored,
 		}
-	}
-	return nil, nil
+
+		d.StreamListItem(ctx, row)
+		return d.RowsRemaining(ctx) != 0
+	})
+	return nil, err
 }
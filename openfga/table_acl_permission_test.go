@@ -2,15 +2,33 @@ package openfga
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/hashicorp/go-hclog"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	gproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// testContext returns a context carrying the logger that plugin.Logger expects
+func testContext() context.Context {
+	return context.WithValue(context.Background(), context_key.Logger, hclog.NewNullLogger())
+}
+
 // testSetup holds the test environment setup
 type testSetup struct {
 	client *Client
@@ -37,7 +55,6 @@ func setUp(t *testing.T) (*testSetup, []testCase) {
 	storeId := os.Getenv("OPENFGA_STORE_ID")
 	modelId := os.Getenv("OPENFGA_AUTHORIZATION_MODEL_ID")
 
-	storeId = "01KA0FSR3W39HES8PTRXA4PDYP"
 	if endpoint == "" {
 		endpoint = "localhost:8081"
 	}
@@ -47,15 +64,15 @@ func setUp(t *testing.T) (*testSetup, []testCase) {
 
 	ctx := context.Background()
 
-	// Create Config for newClient
+	// Create Config for NewClient
 	cfg := Config{
 		Endpoint:             endpoint,
 		StoreId:              &storeId,
 		AuthorizationModelId: &modelId,
 	}
 
-	// Use newClient to create the client
-	client, err := newClient(ctx, cfg)
+	// Use NewClient to create the client
+	client, err := NewClient(ctx, cfg)
 	if err != nil {
 		t.Fatalf("Failed to create OpenFGA client: %v", err)
 	}
@@ -176,7 +193,7 @@ func TestTableAclPermission_Integration(t *testing.T) {
 	setup, testCases := setUp(t)
 	defer tearDown(t, setup, testCases)
 
-	ctx := context.Background()
+	ctx := testContext()
 
 	// Run test cases
 	for _, tc := range testCases {
@@ -209,31 +226,22 @@ func TestTableAclPermission_Integration(t *testing.T) {
 				t.Fatalf("listPermission failed: %v", err)
 			}
 
-			// Verify results
-			if len(results) == 0 {
-				if tc.setupTuple {
-					t.Fatal("Expected at least one result but got none")
-				} else {
-					// If tuple wasn't set up, we don't expect results
-					return
-				}
+			// Verify results: a fully qualified query always yields exactly one row
+			if len(results) != 1 {
+				t.Fatalf("Expected exactly one result but got %d", len(results))
 			}
 
-			// Check that we got the expected tuple
-			found := false
-			for _, result := range results {
-				if result.SubjectID == tc.subjectId &&
-					result.Relation == tc.relation &&
-					result.ObjectType == tc.objectType &&
-					result.ObjectID == tc.objectId {
-					found = true
-					break
-				}
+			result := results[0]
+			if result.SubjectID != tc.subjectId ||
+				result.Relation != tc.relation ||
+				result.ObjectType != tc.objectType ||
+				result.ObjectID != tc.objectId {
+				t.Errorf("Expected tuple (subject=%s:%s, relation=%s, object=%s:%s) but got %+v",
+					tc.subjectType, tc.subjectId, tc.relation, tc.objectType, tc.objectId, result)
 			}
 
-			if !found && tc.setupTuple {
-				t.Errorf("Expected to find tuple (subject=%s:%s, relation=%s, object=%s:%s) but didn't find it",
-					tc.subjectType, tc.subjectId, tc.relation, tc.objectType, tc.objectId)
+			if result.Allowed != tc.expected {
+				t.Errorf("Expected allowed=%v but got %v", tc.expected, result.Allowed)
 			}
 		})
 	}
@@ -241,7 +249,7 @@ func TestTableAclPermission_Integration(t *testing.T) {
 
 // TestTableAclPermission_MissingQuals tests that missing required quals return appropriate errors
 func TestTableAclPermission_MissingQuals(t *testing.T) {
-	ctx := context.Background()
+	ctx := testContext()
 	apiUrl := "http://localhost:8080"
 	storeId := "01ARZ3NDEKTSV4RRFFQ69G5FAV" // Valid ULID format
 
@@ -262,24 +270,24 @@ func TestTableAclPermission_MissingQuals(t *testing.T) {
 			expectNil: false,
 		},
 		{
-			name: "Missing subject_id returns nil (no error, no result)",
+			name: "Missing subject_id falls back to ListUsers",
 			equalsQuals: plugin.KeyColumnEqualsQualMap{
 				"subject_type": &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "user"}},
 				"relation":     &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "viewer"}},
 				"object_type":  &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "document"}},
 				"object_id":    &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "doc1"}},
 			},
-			expectNil: true,
+			expectNil: false,
 		},
 		{
-			name: "Missing subject_type returns nil (no error, no result)",
+			name: "Missing subject_type falls back to Read",
 			equalsQuals: plugin.KeyColumnEqualsQualMap{
 				"subject_id":  &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "alice"}},
 				"relation":    &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "viewer"}},
 				"object_type": &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "document"}},
 				"object_id":   &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: "doc1"}},
 			},
-			expectNil: true,
+			expectNil: false,
 		},
 	}
 
@@ -311,12 +319,439 @@ func TestTableAclPermission_MissingQuals(t *testing.T) {
 					t.Error("StreamListItem should not be called when quals are missing")
 				}
 			} else {
-				// When all quals are provided, it should attempt connection
+				// Every combination that reaches OpenFGA should attempt a connection
 				// This will fail in unit test without real server, which is OK
 				if err == nil {
 					t.Error("Expected connection error in unit test without real server")
 				}
+				if called {
+					t.Error("StreamListItem should not be called when the request fails")
+				}
+			}
+		})
+	}
+}
+
+// TestBatchCheckRows tests that BatchCheck results are correlated back to the requested tuples
+func TestBatchCheckRows(t *testing.T) {
+	tuples := []checkTuple{
+		{ObjectType: "doc", ObjectID: "1", SubjectType: "user", SubjectID: "alice", Relation: "viewer"},
+		{ObjectType: "doc", ObjectID: "1", SubjectType: "user", SubjectID: "bob", Relation: "viewer"},
+		{ObjectType: "doc", ObjectID: "1", SubjectType: "user", SubjectID: "charlie", Relation: "viewer"},
+		{ObjectType: "doc", ObjectID: "1", SubjectType: "user", SubjectID: "dave", Relation: "viewer"},
+	}
+
+	opts := requestOptions{
+		modelID:              "01HMODEL",
+		policyVersion:        "01HMODEL",
+		contextualTuples:     []*openfgav1.TupleKey{{User: "user:bob", Relation: "member", Object: "org:acme"}},
+		contextualTuplesJSON: json.RawMessage(`[{"user":"user:bob","relation":"member","object":"org:acme"}]`),
+	}
+
+	items := batchCheckItems(tuples, opts)
+	if len(items) != len(tuples) {
+		t.Fatalf("batchCheckItems returned %d items, want %d", len(items), len(tuples))
+	}
+	if items[1].GetCorrelationId() != "1" || items[1].GetTupleKey().GetUser() != "user:bob" {
+		t.Fatalf("unexpected batch item: %v", items[1])
+	}
+	if len(items[1].GetContextualTuples().GetTupleKeys()) != 1 {
+		t.Fatalf("batch item is missing the contextual tuples: %v", items[1])
+	}
+
+	res := &openfgav1.BatchCheckResponse{
+		Result: map[string]*openfgav1.BatchCheckSingleResult{
+			"0": {CheckResult: &openfgav1.BatchCheckSingleResult_Allowed{Allowed: true}},
+			"1": {CheckResult: &openfgav1.BatchCheckSingleResult_Allowed{Allowed: false}},
+			"2": {CheckResult: &openfgav1.BatchCheckSingleResult_Error{Error: &openfgav1.CheckError{
+				Code:    &openfgav1.CheckError_InputError{InputError: openfgav1.ErrorCode_relation_not_found},
+				Message: "relation 'viewer' not found",
+			}}},
+		},
+	}
+
+	rows := batchCheckRows(tuples, res, opts, time.Now().UTC())
+	if len(rows) != len(tuples) {
+		t.Fatalf("batchCheckRows returned %d rows, want %d", len(rows), len(tuples))
+	}
+
+	want := []struct {
+		subjectID string
+		allowed   bool
+		hasError  bool
+	}{
+		{"alice", true, false},
+		{"bob", false, false},
+		{"charlie", false, true},
+		{"dave", false, true},
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.SubjectID != w.subjectID || row.Allowed != w.allowed || (row.Error != "") != w.hasError || row.AuthorizationModelID != "01HMODEL" || row.PolicyVersion != "01HMODEL" {
+			t.Errorf("row %d = %+v, want subject=%s allowed=%v error=%v", i, row, w.subjectID, w.allowed, w.hasError)
+		}
+	}
+	if string(rows[0].ContextualTuples) != string(opts.contextualTuplesJSON) {
+		t.Errorf("contextual_tuples = %s, want the qual echoed back", rows[0].ContextualTuples)
+	}
+	if rows[2].Error != "relation_not_found: relation 'viewer' not found" {
+		t.Errorf("unexpected error message %q", rows[2].Error)
+	}
+}
+
+func TestCheckTupleSubjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		tuple    checkTuple
+		wantUser string
+		wantKind string
+	}{
+		{"object", checkTuple{SubjectType: "user", SubjectID: "anne"}, "user:anne", subjectKindObject},
+		{"userset", checkTuple{SubjectType: "group", SubjectID: "eng", SubjectRelation: "member"}, "group:eng#member", subjectKindUserset},
+		{"wildcard", checkTuple{SubjectType: "user", SubjectID: "*"}, "user:*", subjectKindWildcard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tuple.ObjectType, tt.tuple.ObjectID, tt.tuple.Relation = "document", "roadmap", "viewer"
+			key := tt.tuple.tupleKey()
+			if key.GetUser() != tt.wantUser || key.GetObject() != "document:roadmap" || key.GetRelation() != "viewer" {
+				t.Errorf("tupleKey() = %v, want user %q", key, tt.wantUser)
+			}
+			if got := subjectKind(tt.tuple.SubjectID, tt.tuple.SubjectRelation); got != tt.wantKind {
+				t.Errorf("subjectKind() = %q, want %q", got, tt.wantKind)
 			}
 		})
 	}
 }
+
+// streamedObjectsServer streams three documents for every user and records the users it was asked about.
+type streamedObjectsServer struct {
+	fakeOpenFGAServer
+	mu    sync.Mutex
+	users []string
+}
+
+func (s *streamedObjectsServer) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest, stream openfgav1.OpenFGAService_StreamedListObjectsServer) error {
+	s.mu.Lock()
+	s.users = append(s.users, req.GetUser())
+	s.mu.Unlock()
+
+	for i := range 3 {
+		if err := stream.Send(&openfgav1.StreamedListObjectsResponse{Object: fmt.Sprintf("doc:%d", i)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TestListPermission_SubjectListUnderLimit runs the calls the SDK makes for subject_id IN ('alice', 'bob')
+// with LIMIT 1: the leader stops once the limit is reached and the follower sends nothing.
+func TestListPermission_SubjectListUnderLimit(t *testing.T) {
+	srv := &streamedObjectsServer{}
+	addr := startTestServer(t, srv)
+	t.Cleanup(clearClientCache)
+
+	subjects := listQual("alice", "bob")
+	queryContext := &plugin.QueryContext{UnsafeQuals: map[string]*proto.Quals{
+		subjectIDCol: {Quals: []*proto.Qual{{
+			FieldName: subjectIDCol,
+			Operator:  &proto.Qual_StringValue{StringValue: "="},
+			Value:     subjects,
+		}}},
+	}}
+	connection := &plugin.Connection{
+		Name:   "limit_test",
+		Config: &Config{Endpoint: addr, StoreId: ptr("01HSTORE"), AuthorizationModelId: ptr("01HMODEL")},
+	}
+
+	// Postgres ends the scan once the LIMIT is met, which RowsRemaining reports through the cancelled context.
+	const limit = 1
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+
+	var rows []AclPermissionRow
+	for _, subject := range subjects.GetListValue().GetValues() {
+		subjectID := subject.GetStringValue()
+		d := &plugin.QueryData{
+			Connection: connection,
+			EqualsQuals: plugin.KeyColumnEqualsQualMap{
+				objectTypeCol:  stringQual("doc"),
+				subjectTypeCol: stringQual("user"),
+				subjectIDCol:   subject,
+				relationCol:    stringQual("viewer"),
+			},
+			QueryContext: queryContext,
+		}
+		d.StreamListItem = func(_ context.Context, items ...any) {
+			rows = append(rows, items[0].(AclPermissionRow))
+			if len(rows) == limit {
+				cancel()
+			}
+		}
+
+		if _, err := listPermission(ctx, d, nil); err != nil {
+			t.Fatalf("listPermission(subject_id = %s) failed: %v", subjectID, err)
+		}
+	}
+
+	if len(rows) != limit {
+		t.Fatalf("Expected %d rows, got %d: %+v", limit, len(rows), rows)
+	}
+	if !slices.Equal(srv.users, []string{"user:alice"}) {
+		t.Fatalf("Expected a single ListObjects for user:alice, got %v", srv.users)
+	}
+}
+
+// capturedRequest is what an evaluated request, or one BatchCheck item, carried to the server.
+type capturedRequest struct {
+	method           string
+	modelID          string
+	contextualTuples []*openfgav1.TupleKey
+	context          *structpb.Struct
+}
+
+// captureServer answers every acl_permission path with one allowed row for user:anne on doc:1 and records
+// the requests it receives.
+type captureServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	mu       sync.Mutex
+	requests []capturedRequest
+	reads    int
+	checkErr error
+}
+
+func (s *captureServer) record(r capturedRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+}
+
+func (s *captureServer) Check(_ context.Context, req *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	s.record(capturedRequest{"Check", req.GetAuthorizationModelId(), req.GetContextualTuples().GetTupleKeys(), req.GetContext()})
+	if s.checkErr != nil {
+		return nil, s.checkErr
+	}
+	return &openfgav1.CheckResponse{Allowed: true}, nil
+}
+
+func (s *captureServer) BatchCheck(_ context.Context, req *openfgav1.BatchCheckRequest) (*openfgav1.BatchCheckResponse, error) {
+	res := &openfgav1.BatchCheckResponse{Result: map[string]*openfgav1.BatchCheckSingleResult{}}
+	for _, item := range req.GetChecks() {
+		s.record(capturedRequest{"BatchCheck", req.GetAuthorizationModelId(), item.GetContextualTuples().GetTupleKeys(), item.GetContext()})
+		res.Result[item.GetCorrelationId()] = &openfgav1.BatchCheckSingleResult{CheckResult: &openfgav1.BatchCheckSingleResult_Allowed{Allowed: true}}
+	}
+	return res, nil
+}
+
+func (s *captureServer) StreamedListObjects(req *openfgav1.StreamedListObjectsRequest, stream openfgav1.OpenFGAService_StreamedListObjectsServer) error {
+	s.record(capturedRequest{"StreamedListObjects", req.GetAuthorizationModelId(), req.GetContextualTuples().GetTupleKeys(), req.GetContext()})
+	return stream.Send(&openfgav1.StreamedListObjectsResponse{Object: "doc:1"})
+}
+
+func (s *captureServer) ListUsers(_ context.Context, req *openfgav1.ListUsersRequest) (*openfgav1.ListUsersResponse, error) {
+	s.record(capturedRequest{"ListUsers", req.GetAuthorizationModelId(), req.GetContextualTuples(), req.GetContext()})
+	return &openfgav1.ListUsersResponse{Users: []*openfgav1.User{
+		{User: &openfgav1.User_Object{Object: &openfgav1.Object{Type: "user", Id: "anne"}}},
+	}}, nil
+}
+
+func (s *captureServer) Read(_ context.Context, _ *openfgav1.ReadRequest) (*openfgav1.ReadResponse, error) {
+	s.mu.Lock()
+	s.reads++
+	s.mu.Unlock()
+	return &openfgav1.ReadResponse{Tuples: []*openfgav1.Tuple{
+		{Key: &openfgav1.TupleKey{Object: "doc:1", Relation: "viewer", User: "user:anne"}, Timestamp: timestamppb.Now()},
+	}}, nil
+}
+
+// permissionPaths are quals that take each acl_permission path, keyed by the RPC the path sends.
+var permissionPaths = map[string]plugin.KeyColumnEqualsQualMap{
+	"Check": {
+		objectTypeCol: stringQual("doc"), objectIDCol: stringQual("1"),
+		subjectTypeCol: stringQual("user"), subjectIDCol: stringQual("anne"), relationCol: stringQual("viewer"),
+	},
+	"BatchCheck": {
+		objectTypeCol: stringQual("doc"), objectIDCol: stringQual("1"),
+		subjectTypeCol: stringQual("user"), subjectIDCol: listQual("anne", "bob"), relationCol: stringQual("viewer"),
+	},
+	"StreamedListObjects": {
+		objectTypeCol: stringQual("doc"), subjectTypeCol: stringQual("user"), subjectIDCol: stringQual("anne"), relationCol: stringQual("viewer"),
+	},
+	"ListUsers": {
+		objectTypeCol: stringQual("doc"), objectIDCol: stringQual("1"), subjectTypeCol: stringQual("user"), relationCol: stringQual("viewer"),
+	},
+	"Read": {
+		objectTypeCol: stringQual("doc"), objectIDCol: stringQual("1"), relationCol: stringQual("viewer"),
+	},
+}
+
+// firstPermissionRow runs listPermission for the path's quals plus extra and returns the first row it streams.
+func firstPermissionRow(t *testing.T, connection *plugin.Connection, path string, extra plugin.KeyColumnEqualsQualMap) (*AclPermissionRow, error) {
+	t.Helper()
+
+	d := &plugin.QueryData{Connection: connection, EqualsQuals: plugin.KeyColumnEqualsQualMap{}}
+	maps.Copy(d.EqualsQuals, permissionPaths[path])
+	maps.Copy(d.EqualsQuals, extra)
+
+	// RowsRemaining 은 context 가 끝나면 0 을 돌려준다.
+	ctx, cancel := context.WithCancel(testContext())
+	defer cancel()
+
+	var row *AclPermissionRow
+	d.StreamListItem = func(_ context.Context, items ...any) {
+		if row == nil {
+			r := items[0].(AclPermissionRow)
+			row = &r
+		}
+		cancel()
+	}
+
+	_, err := listPermission(ctx, d, nil)
+	return row, err
+}
+
+func TestListPermission_ContextualTuples(t *testing.T) {
+	srv := &captureServer{}
+	addr := startTestServer(t, srv)
+	t.Cleanup(clearClientCache)
+
+	connection := &plugin.Connection{
+		Name:   "contextual_tuples_test",
+		Config: &Config{Endpoint: addr, StoreId: ptr("01HSTORE"), AuthorizationModelId: ptr("01HMODEL")},
+	}
+	const tuplesJSON = `[{"user": "user:anne", "relation": "member", "object": "group:eng"}]`
+	want := &openfgav1.TupleKey{User: "user:anne", Relation: "member", Object: "group:eng"}
+
+	for path := range permissionPaths {
+		t.Run(path, func(t *testing.T) {
+			srv.requests = nil
+
+			row, err := firstPermissionRow(t, connection, path, plugin.KeyColumnEqualsQualMap{contextualTuplesCol: jsonQual(tuplesJSON)})
+			if err != nil || row == nil {
+				t.Fatalf("listPermission = (%v, %v), want a row", row, err)
+			}
+			// Postgres 가 다시 검사하므로 qual 을 그대로 돌려줘야 한다
+			if string(row.ContextualTuples) != tuplesJSON {
+				t.Errorf("contextual_tuples = %s, want %s", row.ContextualTuples, tuplesJSON)
+			}
+
+			if path == "Read" {
+				return
+			}
+			if len(srv.requests) == 0 {
+				t.Fatalf("Expected a %s request", path)
+			}
+			for _, req := range srv.requests {
+				if req.method != path || len(req.contextualTuples) != 1 || !gproto.Equal(req.contextualTuples[0], want) {
+					t.Errorf("%s carried contextual tuples %v, want [%v]", req.method, req.contextualTuples, want)
+				}
+			}
+		})
+	}
+}
+
+func TestListPermission_Context(t *testing.T) {
+	srv := &captureServer{}
+	addr := startTestServer(t, srv)
+	t.Cleanup(clearClientCache)
+
+	connection := &plugin.Connection{
+		Name:   "context_test",
+		Config: &Config{Endpoint: addr, StoreId: ptr("01HSTORE"), AuthorizationModelId: ptr("01HMODEL")},
+	}
+	const contextJSON = `{"current_time": "2024-01-01T00:00:00Z", "max_age": 3}`
+	want, _ := structpb.NewStruct(map[string]any{"current_time": "2024-01-01T00:00:00Z", "max_age": 3})
+
+	for path := range permissionPaths {
+		t.Run(path, func(t *testing.T) {
+			srv.requests = nil
+
+			row, err := firstPermissionRow(t, connection, path, plugin.KeyColumnEqualsQualMap{contextCol: jsonQual(contextJSON)})
+			if err != nil || row == nil {
+				t.Fatalf("listPermission = (%v, %v), want a row", row, err)
+			}
+			if string(row.Context) != contextJSON {
+				t.Errorf("context = %s, want %s", row.Context, contextJSON)
+			}
+
+			if path == "Read" {
+				return
+			}
+			if len(srv.requests) == 0 {
+				t.Fatalf("Expected a %s request", path)
+			}
+			for _, req := range srv.requests {
+				if req.method != path || !gproto.Equal(req.context, want) {
+					t.Errorf("%s carried context %v, want %v", req.method, req.context, want)
+				}
+			}
+		})
+	}
+
+	t.Run("type mismatch", func(t *testing.T) {
+		// OpenFGA 은 condition 파라미터 타입이 맞지 않으면 validation_error 로 실패한다
+		const message = "failed to evaluate relationship condition 'non_expired': failed to convert context parameter 'current_time': expected timestamp"
+		srv.checkErr = status.Error(codes.Code(openfgav1.ErrorCode_validation_error), message)
+		t.Cleanup(func() { srv.checkErr = nil })
+
+		row, err := firstPermissionRow(t, connection, "Check", plugin.KeyColumnEqualsQualMap{contextCol: jsonQual(`{"current_time": 3}`)})
+		if err == nil || row != nil {
+			t.Fatalf("listPermission = (%v, %v), want the server's error and no row", row, err)
+		}
+		if !strings.Contains(err.Error(), "check: ") || !strings.Contains(err.Error(), message) {
+			t.Errorf("error = %q, want the RPC and the server's message", err)
+		}
+	})
+}
+
+func TestListPermission_AuthorizationModelID(t *testing.T) {
+	srv := &captureServer{}
+	addr := startTestServer(t, srv)
+	t.Cleanup(clearClientCache)
+
+	connection := &plugin.Connection{
+		Name:   "model_id_test",
+		Config: &Config{Endpoint: addr, StoreId: ptr("01HSTORE"), AuthorizationModelId: ptr("01HCONFIG")},
+	}
+	tests := []struct {
+		name string
+		qual plugin.KeyColumnEqualsQualMap
+		want string
+	}{
+		{"from config", nil, "01HCONFIG"},
+		{"from qual", plugin.KeyColumnEqualsQualMap{authorizationModelIDCol: stringQual("01HQUAL")}, "01HQUAL"},
+	}
+
+	for _, tt := range tests {
+		for path := range permissionPaths {
+			t.Run(tt.name+"/"+path, func(t *testing.T) {
+				srv.requests = nil
+
+				row, err := firstPermissionRow(t, connection, path, tt.qual)
+				if err != nil || row == nil {
+					t.Fatalf("listPermission = (%v, %v), want a row", row, err)
+				}
+				// Read 는 모델을 받지 않으므로 행에 돌려주는 값만 확인한다
+				if row.AuthorizationModelID != tt.want {
+					t.Errorf("authorization_model_id = %q, want %q", row.AuthorizationModelID, tt.want)
+				}
+
+				if path == "Read" {
+					return
+				}
+				if row.PolicyVersion != tt.want {
+					t.Errorf("policy_version = %q, want %q", row.PolicyVersion, tt.want)
+				}
+				if len(srv.requests) == 0 {
+					t.Fatalf("Expected a %s request", path)
+				}
+				for _, req := range srv.requests {
+					if req.method != path || req.modelID != tt.want {
+						t.Errorf("%s carried authorization_model_id %q, want %q", req.method, req.modelID, tt.want)
+					}
+				}
+			})
+		}
+	}
+}
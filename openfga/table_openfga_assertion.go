@@ -0,0 +1,158 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+type AssertionRow struct {
+	AuthorizationModelID string                `json:"authorization_model_id"`
+	Index                int                   `json:"index"`
+	Object               string                `json:"object"`
+	Relation             string                `json:"relation"`
+	User                 string                `json:"user"`
+	Expectation          bool                  `json:"expectation"`
+	ContextualTuples     []*openfgav1.TupleKey `json:"contextual_tuples"`
+	Context              *structpb.Struct      `json:"context"`
+}
+
+// AssertionResult is the outcome of running an assertion through Check.
+type AssertionResult struct {
+	Actual *bool  `json:"actual"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error"`
+}
+
+func tableOpenFGAAssertion(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_assertion",
+		Description:       "Assertions written for each authorization model, with the result of checking them against that model",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listAssertions,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: authorizationModelIDCol, Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: authorizationModelIDCol, Type: proto.ColumnType_STRING, Description: "Authorization model the assertion belongs to"},
+			{Name: "index", Type: proto.ColumnType_INT, Description: "Position of the assertion in the model's assertion list", Transform: transform.FromField("Index")},
+			{Name: "object", Type: proto.ColumnType_STRING, Description: "Object of the asserted check, e.g. 'document:roadmap'"},
+			{Name: relationCol, Type: proto.ColumnType_STRING, Description: "Relation of the asserted check, e.g. 'viewer'"},
+			{Name: "user", Type: proto.ColumnType_STRING, Description: "User of the asserted check, e.g. 'user:anne'"},
+			{Name: "expectation", Type: proto.ColumnType_BOOL, Description: "Expected Check result", Transform: transform.FromField("Expectation")},
+			{Name: "contextual_tuples", Type: proto.ColumnType_JSON, Description: "Contextual tuples sent with the check", Transform: transform.FromField("ContextualTuples").Transform(protoToJSON)},
+			{Name: "context", Type: proto.ColumnType_JSON, Description: "Condition context sent with the check", Transform: transform.FromField("Context").Transform(protoToJSON)},
+			{Name: "actual", Type: proto.ColumnType_BOOL, Description: "Check result against the assertion's model, null when the check failed", Hydrate: checkAssertion, Transform: transform.FromField("Actual")},
+			{Name: "passed", Type: proto.ColumnType_BOOL, Description: "Whether the check succeeded and matched the expectation", Hydrate: checkAssertion, Transform: transform.FromField("Passed")},
+			{Name: errorCol, Type: proto.ColumnType_STRING, Description: "Error returned by the check, if any", Hydrate: checkAssertion, Transform: transform.FromField("Error")},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listAssertions(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	modelIDs, err := assertionModelIDs(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+
+	err = visitAssertions(ctx, client, modelIDs, func(row AssertionRow) bool {
+		d.StreamListItem(ctx, row)
+		return d.RowsRemaining(ctx) != 0
+	})
+	return nil, err
+}
+
+// visitAssertions reads the assertions of each model and calls visit for every one until visit returns false.
+func visitAssertions(ctx context.Context, client *Client, modelIDs []string, visit func(AssertionRow) bool) error {
+	for _, modelID := range modelIDs {
+		res, err := client.ReadAssertions(ctx, &openfgav1.ReadAssertionsRequest{
+			StoreId:              client.storeID,
+			AuthorizationModelId: modelID,
+		})
+		if err != nil {
+			return fmt.Errorf("ReadAssertions: %w", err)
+		}
+
+		for i, assertion := range res.GetAssertions() {
+			if !visit(newAssertionRow(modelID, i, assertion)) {
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// assertionModelIDs returns the authorization_model_id qual, or the ID of every model in the store.
+func assertionModelIDs(ctx context.Context, d *plugin.QueryData, client *Client) ([]string, error) {
+	modelID, err := singleQualString(d, authorizationModelIDCol)
+	if err != nil || modelID != "" {
+		return []string{modelID}, err
+	}
+
+	var modelIDs []string
+	err = visitAuthorizationModels(ctx, client, "", func(model *openfgav1.AuthorizationModel) bool {
+		modelIDs = append(modelIDs, model.GetId())
+		return true
+	})
+	return modelIDs, err
+}
+
+func newAssertionRow(modelID string, index int, assertion *openfgav1.Assertion) AssertionRow {
+	return AssertionRow{
+		AuthorizationModelID: modelID,
+		Index:                index,
+		Object:               assertion.GetTupleKey().GetObject(),
+		Relation:             assertion.GetTupleKey().GetRelation(),
+		User:                 assertion.GetTupleKey().GetUser(),
+		Expectation:          assertion.GetExpectation(),
+		ContextualTuples:     assertion.GetContextualTuples(),
+		Context:              assertion.GetContext(),
+	}
+}
+
+// checkAssertion runs the assertion through Check against its own model.
+// A failing check does not fail the query; it is reported in the error column and the assertion does not pass.
+func checkAssertion(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	row := h.Item.(AssertionRow)
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &openfgav1.CheckRequest{
+		StoreId:              client.storeID,
+		AuthorizationModelId: row.AuthorizationModelID,
+		TupleKey: &openfgav1.CheckRequestTupleKey{
+			Object:   row.Object,
+			Relation: row.Relation,
+			User:     row.User,
+		},
+		Context:     row.Context,
+		Consistency: client.consistency,
+	}
+	if len(row.ContextualTuples) > 0 {
+		req.ContextualTuples = &openfgav1.ContextualTupleKeys{TupleKeys: row.ContextualTuples}
+	}
+
+	res, err := client.Check(ctx, req)
+	if err != nil {
+		return AssertionResult{Error: err.Error()}, nil
+	}
+
+	actual := res.GetAllowed()
+	return AssertionResult{Actual: &actual, Passed: actual == row.Expectation}, nil
+}
@@ -0,0 +1,123 @@
+package openfga
+
+import (
+	"context"
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// assertionServer holds two models with their assertions. Check allows user:anne only and fails for the
+// relation "broken".
+type assertionServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	checks []*openfgav1.CheckRequest
+}
+
+func (s *assertionServer) ReadAuthorizationModels(_ context.Context, _ *openfgav1.ReadAuthorizationModelsRequest) (*openfgav1.ReadAuthorizationModelsResponse, error) {
+	return &openfgav1.ReadAuthorizationModelsResponse{
+		AuthorizationModels: []*openfgav1.AuthorizationModel{{Id: "m2"}, {Id: "m1"}},
+	}, nil
+}
+
+func (s *assertionServer) ReadAssertions(_ context.Context, req *openfgav1.ReadAssertionsRequest) (*openfgav1.ReadAssertionsResponse, error) {
+	assertion := func(relation, user string, expectation bool) *openfgav1.Assertion {
+		return &openfgav1.Assertion{
+			TupleKey:    &openfgav1.AssertionTupleKey{Object: "document:roadmap", Relation: relation, User: user},
+			Expectation: expectation,
+		}
+	}
+
+	switch req.GetAuthorizationModelId() {
+	case "m2":
+		return &openfgav1.ReadAssertionsResponse{Assertions: []*openfgav1.Assertion{
+			assertion("viewer", "user:anne", true),
+			assertion("viewer", "user:bob", true),
+		}}, nil
+	case "m1":
+		return &openfgav1.ReadAssertionsResponse{Assertions: []*openfgav1.Assertion{
+			assertion("broken", "user:anne", true),
+		}}, nil
+	}
+	return &openfgav1.ReadAssertionsResponse{}, nil
+}
+
+func (s *assertionServer) Check(_ context.Context, req *openfgav1.CheckRequest) (*openfgav1.CheckResponse, error) {
+	s.checks = append(s.checks, req)
+	if req.GetTupleKey().GetRelation() == "broken" {
+		return nil, status.Error(codes.InvalidArgument, "relation 'broken' not found")
+	}
+	return &openfgav1.CheckResponse{Allowed: req.GetTupleKey().GetUser() == "user:anne"}, nil
+}
+
+func TestAssertions(t *testing.T) {
+	srv := &assertionServer{}
+	addr := startTestServer(t, srv)
+	t.Cleanup(clearClientCache)
+
+	connection := &plugin.Connection{
+		Name:   "assertion_test",
+		Config: &Config{Endpoint: addr, StoreId: ptr("a")},
+	}
+	d := &plugin.QueryData{Connection: connection, EqualsQuals: plugin.KeyColumnEqualsQualMap{}}
+	ctx := testContext()
+
+	client, err := getClient(ctx, d)
+	if err != nil {
+		t.Fatalf("getClient failed: %v", err)
+	}
+	modelIDs, err := assertionModelIDs(ctx, d, client)
+	if err != nil {
+		t.Fatalf("assertionModelIDs failed: %v", err)
+	}
+
+	var rows []AssertionRow
+	err = visitAssertions(ctx, client, modelIDs, func(row AssertionRow) bool {
+		rows = append(rows, row)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("visitAssertions failed: %v", err)
+	}
+
+	want := []struct {
+		modelID string
+		index   int
+		user    string
+		actual  *bool
+		passed  bool
+		failed  bool
+	}{
+		{"m2", 0, "user:anne", ptr(true), true, false},
+		{"m2", 1, "user:bob", ptr(false), false, false},
+		{"m1", 0, "user:anne", nil, false, true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Expected %d assertions, got %d: %+v", len(want), len(rows), rows)
+	}
+
+	for i, w := range want {
+		row := rows[i]
+		if row.AuthorizationModelID != w.modelID || row.Index != w.index || row.User != w.user || !row.Expectation {
+			t.Errorf("row %d = %+v, want model %s index %d user %s", i, row, w.modelID, w.index, w.user)
+			continue
+		}
+
+		got, err := checkAssertion(ctx, d, &plugin.HydrateData{Item: row})
+		if err != nil {
+			t.Fatalf("checkAssertion(%d) failed the query: %v", i, err)
+		}
+		result := got.(AssertionResult)
+		if (result.Actual == nil) != (w.actual == nil) || (result.Actual != nil && *result.Actual != *w.actual) ||
+			result.Passed != w.passed || (result.Error != "") != w.failed {
+			t.Errorf("checkAssertion(%d) = %+v, want actual %v passed %v error %v", i, result, w.actual, w.passed, w.failed)
+		}
+	}
+
+	if len(srv.checks) != len(want) || srv.checks[2].GetAuthorizationModelId() != "m1" || srv.checks[2].GetStoreId() != "a" {
+		t.Errorf("checks must run against the assertion's model and store, got %v", srv.checks)
+	}
+}
@@ -0,0 +1,139 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type AuthorizationModelRow struct {
+	ID             string                        `json:"id"`
+	SchemaVersion  string                        `json:"schema_version"`
+	CreatedAt      *time.Time                    `json:"created_at"`
+	TypeCount      int                           `json:"type_count"`
+	ConditionCount int                           `json:"condition_count"`
+	Model          *openfgav1.AuthorizationModel `json:"model"`
+	ModelDSL       string                        `json:"model_dsl"`
+}
+
+// authorizationModelPageSize is the page size used when paging through ReadAuthorizationModels.
+const authorizationModelPageSize = 50
+
+func tableOpenFGAAuthorizationModel(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_authorization_model",
+		Description:       "Authorization models written to the connection's store, newest first",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listAuthorizationModels,
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getAuthorizationModel,
+			KeyColumns: plugin.SingleColumn("id"),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: isNotFoundError,
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "id", Type: proto.ColumnType_STRING, Description: "Authorization model ID (ULID)"},
+			{Name: "schema_version", Type: proto.ColumnType_STRING, Description: "Model schema version, e.g. '1.1'"},
+			{Name: "created_at", Type: proto.ColumnType_TIMESTAMP, Description: "Time the model was written, derived from its ULID"},
+			{Name: "type_count", Type: proto.ColumnType_INT, Description: "Number of type definitions in the model", Transform: transform.FromField("TypeCount")},
+			{Name: "condition_count", Type: proto.ColumnType_INT, Description: "Number of conditions in the model", Transform: transform.FromField("ConditionCount")},
+			{Name: "model", Type: proto.ColumnType_JSON, Description: "Full authorization model in the OpenFGA API JSON format", Transform: transform.FromField("Model").Transform(protoToJSON)},
+			{Name: "model_dsl", Type: proto.ColumnType_STRING, Description: "Authorization model rendered in the OpenFGA DSL; NULL for schema 1.0 models and models without type metadata", Transform: transform.FromField("ModelDSL").Transform(transform.NullIfZeroValue)},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listAuthorizationModels(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = visitAuthorizationModels(ctx, client, "", func(model *openfgav1.AuthorizationModel) bool {
+		d.StreamListItem(ctx, newAuthorizationModelRow(model))
+		return d.RowsRemaining(ctx) != 0
+	})
+	return nil, err
+}
+
+// visitAuthorizationModels calls visit for the model modelID, or for every model in the store when modelID is empty,
+// until visit returns false.
+func visitAuthorizationModels(ctx context.Context, client *Client, modelID string, visit func(*openfgav1.AuthorizationModel) bool) error {
+	if modelID != "" {
+		res, err := client.ReadAuthorizationModel(ctx, &openfgav1.ReadAuthorizationModelRequest{
+			StoreId: client.storeID,
+			Id:      modelID,
+		})
+		if err != nil {
+			return fmt.Errorf("ReadAuthorizationModel: %w", err)
+		}
+		visit(res.GetAuthorizationModel())
+		return nil
+	}
+
+	var continuationToken string
+	for {
+		res, err := client.ReadAuthorizationModels(ctx, &openfgav1.ReadAuthorizationModelsRequest{
+			StoreId:           client.storeID,
+			PageSize:          wrapperspb.Int32(authorizationModelPageSize),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return fmt.Errorf("ReadAuthorizationModels: %w", err)
+		}
+
+		for _, model := range res.GetAuthorizationModels() {
+			if !visit(model) {
+				return nil
+			}
+		}
+
+		continuationToken = res.GetContinuationToken()
+		if continuationToken == "" {
+			return nil
+		}
+	}
+}
+
+func getAuthorizationModel(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	id := d.EqualsQualString("id")
+	if id == "" {
+		return nil, nil
+	}
+
+	res, err := client.ReadAuthorizationModel(ctx, &openfgav1.ReadAuthorizationModelRequest{
+		StoreId: client.storeID,
+		Id:      id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ReadAuthorizationModel: %w", err)
+	}
+	return newAuthorizationModelRow(res.GetAuthorizationModel()), nil
+}
+
+func newAuthorizationModelRow(model *openfgav1.AuthorizationModel) AuthorizationModelRow {
+	return AuthorizationModelRow{
+		ID:             model.GetId(),
+		SchemaVersion:  model.GetSchemaVersion(),
+		CreatedAt:      ulidTime(model.GetId()),
+		TypeCount:      len(model.GetTypeDefinitions()),
+		ConditionCount: len(model.GetConditions()),
+		Model:          model,
+		ModelDSL:       modelDSL(model),
+	}
+}
@@ -0,0 +1,64 @@
+package openfga
+
+import (
+	"context"
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// modelGetServer answers ReadAuthorizationModel like OpenFGA does, with authorization_model_not_found for unknown models.
+type modelGetServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+}
+
+func (s *modelGetServer) ReadAuthorizationModel(_ context.Context, req *openfgav1.ReadAuthorizationModelRequest) (*openfgav1.ReadAuthorizationModelResponse, error) {
+	if req.GetStoreId() == "a" && req.GetId() == "01HMODEL" {
+		return &openfgav1.ReadAuthorizationModelResponse{AuthorizationModel: &openfgav1.AuthorizationModel{
+			Id:              "01HMODEL",
+			SchemaVersion:   "1.1",
+			TypeDefinitions: []*openfgav1.TypeDefinition{{Type: "user"}, {Type: "document"}},
+		}}, nil
+	}
+	return nil, status.Error(codes.Code(openfgav1.ErrorCode_authorization_model_not_found), "authorization_model_not_found")
+}
+
+func TestGetAuthorizationModel(t *testing.T) {
+	addr := startTestServer(t, &modelGetServer{})
+	t.Cleanup(clearClientCache)
+
+	connection := &plugin.Connection{
+		Name:   "get_model_test",
+		Config: &Config{Endpoint: addr, StoreId: ptr("a")},
+	}
+
+	t.Run("existing model", func(t *testing.T) {
+		d := &plugin.QueryData{
+			Connection:  connection,
+			EqualsQuals: plugin.KeyColumnEqualsQualMap{"id": stringQual("01HMODEL")},
+		}
+		got, err := getAuthorizationModel(testContext(), d, nil)
+		if err != nil {
+			t.Fatalf("getAuthorizationModel failed: %v", err)
+		}
+		row := got.(AuthorizationModelRow)
+		if row.ID != "01HMODEL" || row.SchemaVersion != "1.1" || row.TypeCount != 2 {
+			t.Fatalf("getAuthorizationModel = %+v", row)
+		}
+	})
+
+	t.Run("missing model", func(t *testing.T) {
+		ctx := testContext()
+		d := &plugin.QueryData{
+			Connection:  connection,
+			EqualsQuals: plugin.KeyColumnEqualsQualMap{"id": stringQual("01HOTHER")},
+		}
+		got, err := getAuthorizationModel(ctx, d, nil)
+		if err == nil || !isNotFoundError(ctx, d, nil, err) {
+			t.Fatalf("getAuthorizationModel = (%v, %v), want an error the Get config ignores", got, err)
+		}
+	})
+}
@@ -0,0 +1,169 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type ChangeRow struct {
+	Operation        string           `json:"operation"`
+	Type             string           `json:"type"`
+	Object           string           `json:"object"`
+	ObjectID         string           `json:"object_id"`
+	Relation         string           `json:"relation"`
+	User             string           `json:"user"`
+	UserType         string           `json:"user_type"`
+	UserID           string           `json:"user_id"`
+	UserRelation     string           `json:"user_relation"`
+	ConditionName    string           `json:"condition_name"`
+	ConditionContext *structpb.Struct `json:"condition_context"`
+	Timestamp        *time.Time       `json:"timestamp"`
+}
+
+// changePageSize is the page size used when paging through ReadChanges; 100 is the server maximum.
+const changePageSize = 100
+
+func tableOpenFGAChange(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_change",
+		Description:       "Tuple writes and deletes from the store changelog (ReadChanges), oldest first",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listChanges,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "type", Require: plugin.Optional},
+				{Name: "timestamp", Operators: []string{quals.QualOperatorGreaterOrEqual, quals.QualOperatorGreater, quals.QualOperatorLessOrEqual, quals.QualOperatorLess}, Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "operation", Type: proto.ColumnType_STRING, Description: "Change operation: write or delete"},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "Type of the changed object, e.g. 'folder'; passed to ReadChanges as the type filter"},
+			{Name: "object", Type: proto.ColumnType_STRING, Description: "Object of the tuple, e.g. 'folder:finance'"},
+			{Name: objectIDCol, Type: proto.ColumnType_STRING, Description: "ID of the object, e.g. 'finance'"},
+			{Name: relationCol, Type: proto.ColumnType_STRING, Description: "Relation of the tuple, e.g. 'owner'"},
+			{Name: "user", Type: proto.ColumnType_STRING, Description: "User of the tuple: 'user:anne', a userset such as 'group:eng#member' or a wildcard such as 'user:*'"},
+			{Name: "user_type", Type: proto.ColumnType_STRING, Description: "Type of the user, e.g. 'group'"},
+			{Name: "user_id", Type: proto.ColumnType_STRING, Description: "ID of the user, '*' for a wildcard"},
+			{Name: "user_relation", Type: proto.ColumnType_STRING, Description: "Relation of a userset user, e.g. 'member' for 'group:eng#member'"},
+			{Name: "condition_name", Type: proto.ColumnType_STRING, Description: "Name of the condition the tuple was written with"},
+			{Name: "condition_context", Type: proto.ColumnType_JSON, Description: "Context stored with the tuple condition", Transform: transform.FromField("ConditionContext").Transform(protoToJSON)},
+			{Name: "timestamp", Type: proto.ColumnType_TIMESTAMP, Description: "Time of the change; a lower bound is passed to ReadChanges as start_time"},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listChanges(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	objectType, err := singleQualString(d, "type")
+	if err != nil {
+		return nil, err
+	}
+	start, end := changeWindow(d.Quals["timestamp"])
+
+	err = visitChanges(ctx, client, objectType, start, func(change *openfgav1.TupleChange) bool {
+		// 변경 로그는 시간순이므로 상한을 넘으면 더 읽을 필요가 없다.
+		if end != nil && change.GetTimestamp().AsTime().After(*end) {
+			return false
+		}
+		d.StreamListItem(ctx, newChangeRow(change))
+		return d.RowsRemaining(ctx) != 0
+	})
+	return nil, err
+}
+
+// changeWindow returns the lower bound to send as start_time and the upper bound after which paging can stop.
+// Both are inclusive; Postgres rechecks the strict operators.
+func changeWindow(q *plugin.KeyColumnQuals) (start *timestamppb.Timestamp, end *time.Time) {
+	if q == nil {
+		return nil, nil
+	}
+
+	for _, qual := range q.Quals {
+		ts := qual.Value.GetTimestampValue()
+		if ts == nil {
+			continue
+		}
+		t := ts.AsTime()
+
+		switch qual.Operator {
+		case quals.QualOperatorGreaterOrEqual, quals.QualOperatorGreater:
+			if start == nil || t.After(start.AsTime()) {
+				start = timestamppb.New(t)
+			}
+		case quals.QualOperatorLessOrEqual, quals.QualOperatorLess:
+			if end == nil || t.Before(*end) {
+				end = &t
+			}
+		}
+	}
+	return start, end
+}
+
+// visitChanges pages through ReadChanges and calls visit for every change until visit returns false.
+// ReadChanges always hands back a continuation token, so paging stops at the first empty page.
+func visitChanges(ctx context.Context, client *Client, objectType string, startTime *timestamppb.Timestamp, visit func(*openfgav1.TupleChange) bool) error {
+	req := &openfgav1.ReadChangesRequest{
+		StoreId:   client.storeID,
+		Type:      objectType,
+		PageSize:  wrapperspb.Int32(changePageSize),
+		StartTime: startTime,
+	}
+
+	for {
+		res, err := client.ReadChanges(ctx, req)
+		if err != nil {
+			return fmt.Errorf("ReadChanges: %w", err)
+		}
+
+		for _, change := range res.GetChanges() {
+			if !visit(change) {
+				return nil
+			}
+		}
+
+		token := res.GetContinuationToken()
+		if len(res.GetChanges()) == 0 || token == "" || token == req.GetContinuationToken() {
+			return nil
+		}
+		// continuation token 이 start_time 보다 우선하므로 두 번째 페이지부터는 보내지 않는다.
+		req.ContinuationToken = token
+		req.StartTime = nil
+	}
+}
+
+func newChangeRow(change *openfgav1.TupleChange) ChangeRow {
+	key := change.GetTupleKey()
+	objectType, objectID := splitObject(key.GetObject())
+	userType, userID, userRelation := splitUser(key.GetUser())
+
+	return ChangeRow{
+		Operation:        strings.ToLower(strings.TrimPrefix(change.GetOperation().String(), "TUPLE_OPERATION_")),
+		Type:             objectType,
+		Object:           key.GetObject(),
+		ObjectID:         objectID,
+		Relation:         key.GetRelation(),
+		User:             key.GetUser(),
+		UserType:         userType,
+		UserID:           userID,
+		UserRelation:     userRelation,
+		ConditionName:    key.GetCondition().GetName(),
+		ConditionContext: key.GetCondition().GetContext(),
+		Timestamp:        timeValue(change.GetTimestamp()),
+	}
+}
@@ -0,0 +1,119 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// changeLogServer serves changes in pages of two and, like OpenFGA, keeps returning a token after the last page.
+type changeLogServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	changes  []*openfgav1.TupleChange
+	requests []*openfgav1.ReadChangesRequest
+}
+
+func (s *changeLogServer) ReadChanges(_ context.Context, req *openfgav1.ReadChangesRequest) (*openfgav1.ReadChangesResponse, error) {
+	s.requests = append(s.requests, req)
+
+	offset := 0
+	if req.GetContinuationToken() != "" {
+		offset, _ = strconv.Atoi(req.GetContinuationToken())
+	}
+	end := min(offset+2, len(s.changes))
+	return &openfgav1.ReadChangesResponse{
+		Changes:           s.changes[offset:end],
+		ContinuationToken: strconv.Itoa(end),
+	}, nil
+}
+
+func TestVisitChanges(t *testing.T) {
+	srv := &changeLogServer{}
+	for i := range 5 {
+		srv.changes = append(srv.changes, &openfgav1.TupleChange{
+			TupleKey:  &openfgav1.TupleKey{Object: fmt.Sprintf("folder:%d", i), Relation: "owner", User: "user:anne"},
+			Operation: openfgav1.TupleOperation_TUPLE_OPERATION_WRITE,
+		})
+	}
+
+	client, err := NewClient(context.Background(), Config{Endpoint: startTestServer(t, srv), StoreId: ptr("store")})
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	start := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	t.Run("pages until an empty page", func(t *testing.T) {
+		srv.requests = nil
+		var got int
+		err := visitChanges(context.Background(), client, "folder", start, func(*openfgav1.TupleChange) bool {
+			got++
+			return true
+		})
+		if err != nil {
+			t.Fatalf("visitChanges failed: %v", err)
+		}
+		if got != 5 {
+			t.Errorf("visited %d changes, want 5", got)
+		}
+		// 2 + 2 + 1 + 빈 페이지
+		if len(srv.requests) != 4 {
+			t.Errorf("sent %d requests, want 4", len(srv.requests))
+		}
+		if srv.requests[0].GetStartTime() == nil || srv.requests[1].GetStartTime() != nil {
+			t.Errorf("start_time must only be sent on the first page")
+		}
+		if srv.requests[0].GetType() != "folder" {
+			t.Errorf("type = %q, want folder", srv.requests[0].GetType())
+		}
+	})
+
+	t.Run("stops when visit returns false", func(t *testing.T) {
+		srv.requests = nil
+		var got int
+		err := visitChanges(context.Background(), client, "", nil, func(*openfgav1.TupleChange) bool {
+			got++
+			return got < 3
+		})
+		if err != nil {
+			t.Fatalf("visitChanges failed: %v", err)
+		}
+		if got != 3 || len(srv.requests) != 2 {
+			t.Errorf("visited %d changes in %d requests, want 3 in 2", got, len(srv.requests))
+		}
+	})
+}
+
+func TestChangeWindow(t *testing.T) {
+	day := func(d int) *proto.QualValue {
+		return &proto.QualValue{Value: &proto.QualValue_TimestampValue{
+			TimestampValue: timestamppb.New(time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)),
+		}}
+	}
+
+	start, end := changeWindow(&plugin.KeyColumnQuals{Quals: quals.QualSlice{
+		{Operator: quals.QualOperatorGreaterOrEqual, Value: day(1)},
+		{Operator: quals.QualOperatorGreater, Value: day(3)},
+		{Operator: quals.QualOperatorLess, Value: day(9)},
+		{Operator: quals.QualOperatorLessOrEqual, Value: day(7)},
+	}})
+	if start.AsTime().Day() != 3 {
+		t.Errorf("start = %v, want the latest lower bound", start.AsTime())
+	}
+	if end == nil || end.Day() != 7 {
+		t.Errorf("end = %v, want the earliest upper bound", end)
+	}
+
+	if start, end := changeWindow(nil); start != nil || end != nil {
+		t.Errorf("changeWindow(nil) = (%v, %v), want no bounds", start, end)
+	}
+}
@@ -0,0 +1,161 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfgainternal "github.com/carped99/steampipe-plugin-openfga/internal/openfga"
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type ConnectionStatusRow struct {
+	Endpoint           string     `json:"endpoint"`
+	TLSMode            string     `json:"tls_mode"`
+	InsecureSkipVerify bool       `json:"insecure_skip_verify"`
+	AuthMode           string     `json:"auth_mode"`
+	HealthStatus       string     `json:"health_status"`
+	LatencyMs          float64    `json:"latency_ms"`
+	StoreID            string     `json:"store_id"`
+	StoreName          string     `json:"store_name"`
+	StoreDeleted       bool       `json:"store_deleted"`
+	StoreDeletedAt     *time.Time `json:"store_deleted_at"`
+	ModelID            string     `json:"model_id"`
+	LastError          string     `json:"last_error"`
+}
+
+func tableOpenFGAConnectionStatus(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "openfga_connection_status",
+		Description: "Health of the connection: server reachability, authentication and the configured stores and model, one row per store",
+		List: &plugin.ListConfig{
+			Hydrate: listConnectionStatus,
+		},
+		Columns: []*plugin.Column{
+			{Name: "endpoint", Type: proto.ColumnType_STRING, Description: "OpenFGA gRPC endpoint of the connection"},
+			{Name: "tls_mode", Type: proto.ColumnType_STRING, Description: "Transport: plaintext, tls or mtls"},
+			{Name: "insecure_skip_verify", Type: proto.ColumnType_BOOL, Description: "Whether the server certificate is not verified", Transform: transform.FromField("InsecureSkipVerify")},
+			{Name: "auth_mode", Type: proto.ColumnType_STRING, Description: "Authentication: none, api_token or client_credentials"},
+			{Name: "health_status", Type: proto.ColumnType_STRING, Description: "Status reported by the gRPC health service, e.g. SERVING, NULL when it could not be reached"},
+			{Name: "latency_ms", Type: proto.ColumnType_DOUBLE, Description: "Round-trip time of the health check in milliseconds", Transform: transform.FromField("LatencyMs")},
+			{Name: "store_id", Type: proto.ColumnType_STRING, Description: "Configured store, NULL when the connection has none"},
+			{Name: "store_name", Type: proto.ColumnType_STRING, Description: "Name of the store"},
+			{Name: "store_deleted", Type: proto.ColumnType_BOOL, Description: "Whether the store has been deleted", Transform: transform.FromField("StoreDeleted")},
+			{Name: "store_deleted_at", Type: proto.ColumnType_TIMESTAMP, Description: "Time the store was deleted"},
+			{Name: "model_id", Type: proto.ColumnType_STRING, Description: "Authorization model queries use: the pinned model or the latest model of the store"},
+			{Name: "last_error", Type: proto.ColumnType_STRING, Description: "First error met while checking the connection, NULL when every check passed"},
+		},
+	}
+}
+
+// listConnectionStatus never fails the query: errors end up in last_error so the table stays usable
+// exactly when the other tables are not.
+func listConnectionStatus(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	ctx, cancel := context.WithTimeout(ctx, validationTimeout)
+	defer cancel()
+
+	client, err := getConnectionClient(ctx, d)
+	for _, row := range connectionStatusRows(ctx, d.Connection.Name, getConfig(d.Connection), client, err) {
+		d.StreamListItem(ctx, row)
+	}
+	return nil, nil
+}
+
+// connectionStatusRows probes the connection's client, or, when it could not be set up (clientErr), a fresh
+// unvalidated client so the rows still tell whether the server is reachable and what state the stores are in,
+// e.g. the deleted store that failed validate_connection. Such rows keep clientErr as last_error.
+func connectionStatusRows(ctx context.Context, connName string, cfg Config, client *Client, clientErr error) []ConnectionStatusRow {
+	base := ConnectionStatusRow{
+		Endpoint:           cfg.Endpoint,
+		TLSMode:            tlsMode(cfg),
+		InsecureSkipVerify: cfg.InsecureSkipVerify != nil && *cfg.InsecureSkipVerify,
+	}
+
+	if clientErr != nil {
+		base.LastError = clientErr.Error()
+		probe, err := NewClient(ctx, cfg)
+		if err != nil {
+			return []ConnectionStatusRow{base}
+		}
+		defer probe.Close()
+		probe.connectionName = connName
+		client = probe
+	}
+
+	base.AuthMode = client.authMode
+	base.probeHealth(ctx, client)
+
+	storeIDs, err := client.configuredStores(ctx)
+	if err != nil {
+		base.setError(err)
+	}
+	if len(storeIDs) == 0 {
+		return []ConnectionStatusRow{base}
+	}
+
+	rows := make([]ConnectionStatusRow, 0, len(storeIDs))
+	for _, storeID := range storeIDs {
+		row := base
+		row.probeStore(ctx, client.withStore(storeID))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (r *ConnectionStatusRow) probeHealth(ctx context.Context, client *Client) {
+	start := time.Now()
+	status, err := openfgainternal.CheckHealth(ctx, client.conn)
+	r.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+
+	if err != nil {
+		r.setError(fmt.Errorf("health check: %w", err))
+	}
+	if status != grpc_health_v1.HealthCheckResponse_UNKNOWN {
+		r.HealthStatus = status.String()
+	}
+}
+
+// probeStore checks the client's store and resolves the model its queries use.
+func (r *ConnectionStatusRow) probeStore(ctx context.Context, client *Client) {
+	r.StoreID = client.storeID
+
+	store, err := client.GetStore(ctx, &openfgav1.GetStoreRequest{StoreId: client.storeID})
+	if err != nil {
+		r.setError(fmt.Errorf("GetStore: %w", err))
+		return
+	}
+	r.StoreName = store.GetName()
+	r.StoreDeletedAt = timeValue(store.GetDeletedAt())
+	r.StoreDeleted = r.StoreDeletedAt != nil
+	if r.StoreDeleted {
+		r.setError(fmt.Errorf("store %s [%s] is deleted", store.GetId(), store.GetName()))
+		return
+	}
+
+	if client.modelID != "" {
+		// resolveModelID trusts a pinned model, so confirm it exists
+		err := visitAuthorizationModels(ctx, client, client.modelID, func(*openfgav1.AuthorizationModel) bool { return false })
+		if err != nil {
+			r.setError(err)
+			return
+		}
+	}
+
+	modelID, err := client.resolveModelID(ctx, client.modelID)
+	if err != nil {
+		r.setError(err)
+		return
+	}
+	r.ModelID = modelID
+}
+
+// setError keeps the first error, later checks usually fail because of it.
+func (r *ConnectionStatusRow) setError(err error) {
+	if r.LastError == "" {
+		r.LastError = err.Error()
+	}
+}
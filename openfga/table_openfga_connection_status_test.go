@@ -0,0 +1,86 @@
+package openfga
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestConnectionStatusRows(t *testing.T) {
+	addr := startHealthTestServer(t, &storeCatalogServer{}, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	newTestClient := func(t *testing.T, cfg Config) *Client {
+		t.Helper()
+		client, err := NewClient(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		t.Cleanup(func() { _ = client.Close() })
+		return client
+	}
+
+	t.Run("healthy stores", func(t *testing.T) {
+		cfg := Config{Endpoint: addr, StoreIds: []string{"live", "gone"}}
+		rows := connectionStatusRows(context.Background(), "status_test", cfg, newTestClient(t, cfg), nil)
+		if len(rows) != 2 {
+			t.Fatalf("Expected 2 rows, got %d", len(rows))
+		}
+
+		live, gone := rows[0], rows[1]
+		if live.Endpoint != addr || live.TLSMode != "plaintext" || live.AuthMode != authModeNone || live.HealthStatus != "SERVING" {
+			t.Errorf("Unexpected connection columns: %+v", live)
+		}
+		if live.StoreName != "live" || live.StoreDeleted || live.ModelID != "m1" || live.LastError != "" {
+			t.Errorf("Unexpected live store row: %+v", live)
+		}
+		if !gone.StoreDeleted || gone.StoreDeletedAt == nil || gone.ModelID != "" || !strings.Contains(gone.LastError, "deleted") {
+			t.Errorf("Unexpected deleted store row: %+v", gone)
+		}
+	})
+
+	t.Run("missing pinned model", func(t *testing.T) {
+		cfg := Config{Endpoint: addr, StoreId: ptr("live"), AuthorizationModelId: ptr("m2")}
+		rows := connectionStatusRows(context.Background(), "status_test", cfg, newTestClient(t, cfg), nil)
+		if len(rows) != 1 || rows[0].ModelID != "" || !strings.Contains(rows[0].LastError, "ReadAuthorizationModel") {
+			t.Fatalf("Unexpected rows: %+v", rows)
+		}
+	})
+
+	t.Run("client setup failed", func(t *testing.T) {
+		cfg := Config{Endpoint: addr, StoreName: ptr("qa")}
+		rows := connectionStatusRows(context.Background(), "status_test", cfg, nil, errors.New(`store_name: no active OpenFGA store named "qa"`))
+		if len(rows) != 1 {
+			t.Fatalf("Expected 1 row, got %d", len(rows))
+		}
+		if rows[0].HealthStatus != "SERVING" || !strings.HasPrefix(rows[0].LastError, "store_name:") {
+			t.Fatalf("Expected the server to be probed despite the setup error, got %+v", rows[0])
+		}
+	})
+
+	t.Run("deleted store failed validation", func(t *testing.T) {
+		cfg := Config{Endpoint: addr, StoreId: ptr("gone"), ValidateConnection: ptr(true)}
+		clientErr := errors.New(`connection "status_test": validate_connection: store gone is deleted`)
+		rows := connectionStatusRows(context.Background(), "status_test", cfg, nil, clientErr)
+		if len(rows) != 1 {
+			t.Fatalf("Expected 1 row, got %d", len(rows))
+		}
+		row := rows[0]
+		if row.StoreID != "gone" || row.StoreName != "gone" || !row.StoreDeleted || row.StoreDeletedAt == nil {
+			t.Errorf("Expected the store to be probed despite the setup error, got %+v", row)
+		}
+		if row.LastError != clientErr.Error() {
+			t.Errorf("last_error = %q, want the setup error", row.LastError)
+		}
+	})
+
+	t.Run("not serving", func(t *testing.T) {
+		cfg := Config{Endpoint: startHealthTestServer(t, &storeCatalogServer{}, grpc_health_v1.HealthCheckResponse_NOT_SERVING)}
+		rows := connectionStatusRows(context.Background(), "status_test", cfg, newTestClient(t, cfg), nil)
+		if len(rows) != 1 || rows[0].HealthStatus != "NOT_SERVING" || !strings.Contains(rows[0].LastError, "not serving") {
+			t.Fatalf("Unexpected rows: %+v", rows)
+		}
+	})
+}
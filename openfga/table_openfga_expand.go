@@ -0,0 +1,173 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type ExpandRow struct {
+	Object               string   `json:"object"`
+	Relation             string   `json:"relation"`
+	AuthorizationModelID string   `json:"authorization_model_id"`
+	PolicyVersion        string   `json:"policy_version"`
+	Path                 string   `json:"path"`
+	ParentPath           string   `json:"parent_path"`
+	Depth                int      `json:"depth"`
+	Position             int      `json:"position"`
+	Name                 string   `json:"name"`
+	Kind                 string   `json:"kind"`
+	Tupleset             string   `json:"tupleset"`
+	Users                []string `json:"users"`
+	Consistency          string   `json:"consistency"`
+}
+
+func tableOpenFGAExpand(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_expand",
+		Description:       "Nodes of the userset tree returned by Expand for an object and relation, one row per node",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listExpand,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "object", Require: plugin.Required},
+				{Name: relationCol, Require: plugin.Required},
+				{Name: authorizationModelIDCol, Require: plugin.Optional},
+				{Name: consistencyCol, Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "object", Type: proto.ColumnType_STRING, Description: "Expanded object, e.g. 'document:roadmap'"},
+			{Name: relationCol, Type: proto.ColumnType_STRING, Description: "Expanded relation, e.g. 'viewer'"},
+			{Name: authorizationModelIDCol, Type: proto.ColumnType_STRING, Description: "Authorization model to expand with; defaults to the connection's model"},
+			{Name: "policy_version", Type: proto.ColumnType_STRING, Description: "Authorization model that actually produced the tree"},
+			{Name: "path", Type: proto.ColumnType_STRING, Description: "Path of the node in the tree: '0' for the root, '0.1' for its second child and so on"},
+			{Name: "parent_path", Type: proto.ColumnType_STRING, Description: "Path of the parent node, null for the root"},
+			{Name: "depth", Type: proto.ColumnType_INT, Description: "Depth of the node, 0 for the root", Transform: transform.FromField("Depth")},
+			{Name: "position", Type: proto.ColumnType_INT, Description: "Position of the node under its parent; for a difference 0 is the base and 1 the subtracted set", Transform: transform.FromField("Position")},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Userset the node stands for, e.g. 'document:roadmap#viewer'"},
+			{Name: "kind", Type: proto.ColumnType_STRING, Description: "Node kind: users, computed, tuple_to_userset, union, intersection or difference"},
+			{Name: "tupleset", Type: proto.ColumnType_STRING, Description: "Tupleset of a tuple_to_userset leaf, e.g. 'document:roadmap#parent'"},
+			{Name: "users", Type: proto.ColumnType_JSON, Description: "Users or usersets at a leaf: the users of a users leaf, the userset of a computed leaf or the computed usersets of a tuple_to_userset leaf"},
+			{Name: consistencyCol, Type: proto.ColumnType_STRING, Description: "Consistency preference of the request: minimize_latency, higher_consistency or unspecified; defaults to the connection's consistency"},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listExpand(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := singleQualString(d, "object")
+	if err != nil {
+		return nil, err
+	}
+	relation, err := singleQualString(d, relationCol)
+	if err != nil {
+		return nil, err
+	}
+
+	opts, err := newEvaluationOptions(ctx, d, client)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Expand(ctx, &openfgav1.ExpandRequest{
+		StoreId:              client.storeID,
+		AuthorizationModelId: opts.policyVersion,
+		TupleKey: &openfgav1.ExpandRequestTupleKey{
+			Object:   object,
+			Relation: relation,
+		},
+		Consistency: opts.consistency,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("expand: %w", err)
+	}
+
+	for _, row := range flattenUsersetTree(res.GetTree().GetRoot()) {
+		row.Object = object
+		row.Relation = relation
+		row.AuthorizationModelID = opts.modelID
+		row.PolicyVersion = opts.policyVersion
+		row.Consistency = opts.consistencyText
+
+		d.StreamListItem(ctx, row)
+		if d.RowsRemaining(ctx) == 0 {
+			break
+		}
+	}
+	return nil, nil
+}
+
+// flattenUsersetTree lists the nodes of an Expand tree depth first, parents before their children.
+func flattenUsersetTree(root *openfgav1.UsersetTree_Node) []ExpandRow {
+	if root == nil {
+		return nil
+	}
+
+	var rows []ExpandRow
+	var walk func(node *openfgav1.UsersetTree_Node, parentPath string, depth, position int)
+	walk = func(node *openfgav1.UsersetTree_Node, parentPath string, depth, position int) {
+		path := strconv.Itoa(position)
+		if parentPath != "" {
+			path = parentPath + "." + path
+		}
+
+		row := ExpandRow{
+			Path:       path,
+			ParentPath: parentPath,
+			Depth:      depth,
+			Position:   position,
+			Name:       node.GetName(),
+		}
+
+		var children []*openfgav1.UsersetTree_Node
+		switch v := node.GetValue().(type) {
+		case *openfgav1.UsersetTree_Node_Leaf:
+			switch leaf := v.Leaf.GetValue().(type) {
+			case *openfgav1.UsersetTree_Leaf_Users:
+				row.Kind = "users"
+				row.Users = leaf.Users.GetUsers()
+			case *openfgav1.UsersetTree_Leaf_Computed:
+				row.Kind = "computed"
+				row.Users = []string{leaf.Computed.GetUserset()}
+			case *openfgav1.UsersetTree_Leaf_TupleToUserset:
+				row.Kind = "tuple_to_userset"
+				row.Tupleset = leaf.TupleToUserset.GetTupleset()
+				for _, computed := range leaf.TupleToUserset.GetComputed() {
+					row.Users = append(row.Users, computed.GetUserset())
+				}
+			}
+			if row.Users == nil {
+				row.Users = []string{}
+			}
+		case *openfgav1.UsersetTree_Node_Union:
+			row.Kind = "union"
+			children = v.Union.GetNodes()
+		case *openfgav1.UsersetTree_Node_Intersection:
+			row.Kind = "intersection"
+			children = v.Intersection.GetNodes()
+		case *openfgav1.UsersetTree_Node_Difference:
+			row.Kind = "difference"
+			children = []*openfgav1.UsersetTree_Node{v.Difference.GetBase(), v.Difference.GetSubtract()}
+		}
+
+		rows = append(rows, row)
+		for i, child := range children {
+			if child != nil {
+				walk(child, path, depth+1, i)
+			}
+		}
+	}
+	walk(root, "", 0, 0)
+	return rows
+}
@@ -0,0 +1,63 @@
+package openfga
+
+import (
+	"slices"
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+)
+
+func TestFlattenUsersetTree(t *testing.T) {
+	usersLeaf := func(name string, users ...string) *openfgav1.UsersetTree_Node {
+		return &openfgav1.UsersetTree_Node{Name: name, Value: &openfgav1.UsersetTree_Node_Leaf{Leaf: &openfgav1.UsersetTree_Leaf{
+			Value: &openfgav1.UsersetTree_Leaf_Users{Users: &openfgav1.UsersetTree_Users{Users: users}},
+		}}}
+	}
+
+	root := &openfgav1.UsersetTree_Node{
+		Name: "document:roadmap#viewer",
+		Value: &openfgav1.UsersetTree_Node_Difference{Difference: &openfgav1.UsersetTree_Difference{
+			Base: &openfgav1.UsersetTree_Node{
+				Name: "document:roadmap#viewer",
+				Value: &openfgav1.UsersetTree_Node_Union{Union: &openfgav1.UsersetTree_Nodes{Nodes: []*openfgav1.UsersetTree_Node{
+					usersLeaf("document:roadmap#viewer", "user:anne", "group:eng#member", "user:*"),
+					{Name: "document:roadmap#viewer", Value: &openfgav1.UsersetTree_Node_Leaf{Leaf: &openfgav1.UsersetTree_Leaf{
+						Value: &openfgav1.UsersetTree_Leaf_Computed{Computed: &openfgav1.UsersetTree_Computed{Userset: "document:roadmap#editor"}},
+					}}},
+					{Name: "document:roadmap#viewer", Value: &openfgav1.UsersetTree_Node_Leaf{Leaf: &openfgav1.UsersetTree_Leaf{
+						Value: &openfgav1.UsersetTree_Leaf_TupleToUserset{TupleToUserset: &openfgav1.UsersetTree_TupleToUserset{
+							Tupleset: "document:roadmap#parent",
+							Computed: []*openfgav1.UsersetTree_Computed{{Userset: "folder:plans#viewer"}},
+						}},
+					}}},
+				}}},
+			},
+			Subtract: usersLeaf("document:roadmap#blocked"),
+		}},
+	}
+
+	want := []ExpandRow{
+		{Path: "0", Depth: 0, Kind: "difference"},
+		{Path: "0.0", ParentPath: "0", Depth: 1, Position: 0, Kind: "union"},
+		{Path: "0.0.0", ParentPath: "0.0", Depth: 2, Position: 0, Kind: "users", Users: []string{"user:anne", "group:eng#member", "user:*"}},
+		{Path: "0.0.1", ParentPath: "0.0", Depth: 2, Position: 1, Kind: "computed", Users: []string{"document:roadmap#editor"}},
+		{Path: "0.0.2", ParentPath: "0.0", Depth: 2, Position: 2, Kind: "tuple_to_userset", Tupleset: "document:roadmap#parent", Users: []string{"folder:plans#viewer"}},
+		{Path: "0.1", ParentPath: "0", Depth: 1, Position: 1, Kind: "users", Users: []string{}},
+	}
+
+	got := flattenUsersetTree(root)
+	if len(got) != len(want) {
+		t.Fatalf("got %d rows, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Path != w.Path || g.ParentPath != w.ParentPath || g.Depth != w.Depth || g.Position != w.Position ||
+			g.Kind != w.Kind || g.Tupleset != w.Tupleset || !slices.Equal(g.Users, w.Users) {
+			t.Errorf("row %d = %+v, want %+v", i, g, w)
+		}
+	}
+
+	if rows := flattenUsersetTree(nil); rows != nil {
+		t.Errorf("flattenUsersetTree(nil) = %v, want nil", rows)
+	}
+}
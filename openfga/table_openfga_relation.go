@@ -0,0 +1,117 @@
+package openfga
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type RelationRow struct {
+	AuthorizationModelID     string             `json:"authorization_model_id"`
+	Type                     string             `json:"type"`
+	Relation                 string             `json:"relation"`
+	DirectlyRelatedUserTypes []string           `json:"directly_related_user_types"`
+	AcceptsWildcard          bool               `json:"accepts_wildcard"`
+	HasCondition             bool               `json:"has_condition"`
+	Conditions               []string           `json:"conditions"`
+	RewriteKind              string             `json:"rewrite_kind"`
+	Rewrite                  string             `json:"rewrite"`
+	Definition               *openfgav1.Userset `json:"definition"`
+}
+
+func tableOpenFGARelation(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_relation",
+		Description:       "One row per (type, relation) of every authorization model in the store",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listRelations,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: authorizationModelIDCol, Require: plugin.Optional},
+				{Name: "type", Require: plugin.Optional},
+				{Name: relationCol, Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: authorizationModelIDCol, Type: proto.ColumnType_STRING, Description: "Authorization model the relation belongs to"},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "Type that defines the relation, e.g. 'document'"},
+			{Name: relationCol, Type: proto.ColumnType_STRING, Description: "Relation name, e.g. 'viewer'"},
+			{Name: "directly_related_user_types", Type: proto.ColumnType_JSON, Description: "User types that can be assigned directly, e.g. [\"user\", \"group#member\", \"user:*\"]"},
+			{Name: "accepts_wildcard", Type: proto.ColumnType_BOOL, Description: "Whether a public wildcard such as 'user:*' can be assigned directly", Transform: transform.FromField("AcceptsWildcard")},
+			{Name: "has_condition", Type: proto.ColumnType_BOOL, Description: "Whether any directly related user type is guarded by a condition", Transform: transform.FromField("HasCondition")},
+			{Name: "conditions", Type: proto.ColumnType_JSON, Description: "Conditions guarding the directly related user types; directly_related_user_types lists the types without them"},
+			{Name: "rewrite_kind", Type: proto.ColumnType_STRING, Description: "Top-level rewrite operator: this, computed_userset, tuple_to_userset, union, intersection or difference"},
+			{Name: "rewrite", Type: proto.ColumnType_STRING, Description: "Rewrite rendered in DSL notation, with 'this' for direct assignment, e.g. 'this or editor or viewer from parent'"},
+			{Name: "definition", Type: proto.ColumnType_JSON, Description: "Relation rewrite in the OpenFGA API JSON format", Transform: transform.FromField("Definition").Transform(protoToJSON)},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listRelations(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	modelID := filterQualString(d, authorizationModelIDCol)
+	typeName := filterQualString(d, "type")
+	relation := filterQualString(d, relationCol)
+
+	err = visitAuthorizationModels(ctx, client, modelID, func(model *openfgav1.AuthorizationModel) bool {
+		for _, typeDef := range model.GetTypeDefinitions() {
+			if typeName != "" && typeDef.GetType() != typeName {
+				continue
+			}
+
+			for _, name := range slices.Sorted(maps.Keys(typeDef.GetRelations())) {
+				if relation != "" && name != relation {
+					continue
+				}
+
+				d.StreamListItem(ctx, newRelationRow(model.GetId(), typeDef, name))
+				if d.RowsRemaining(ctx) == 0 {
+					return false
+				}
+			}
+		}
+		return true
+	})
+	return nil, err
+}
+
+func newRelationRow(modelID string, typeDef *openfgav1.TypeDefinition, relation string) RelationRow {
+	rewrite := typeDef.GetRelations()[relation]
+	row := RelationRow{
+		AuthorizationModelID:     modelID,
+		Type:                     typeDef.GetType(),
+		Relation:                 relation,
+		DirectlyRelatedUserTypes: []string{},
+		Conditions:               []string{},
+		RewriteKind:              rewriteKind(rewrite),
+		Rewrite:                  rewriteExpression(rewrite, "this"),
+		Definition:               rewrite,
+	}
+
+	for _, ref := range typeDef.GetMetadata().GetRelations()[relation].GetDirectlyRelatedUserTypes() {
+		// "user"와 "user with cond"는 같은 타입 참조다
+		if t := relationReferenceString(ref); !slices.Contains(row.DirectlyRelatedUserTypes, t) {
+			row.DirectlyRelatedUserTypes = append(row.DirectlyRelatedUserTypes, t)
+		}
+		if ref.GetWildcard() != nil {
+			row.AcceptsWildcard = true
+		}
+		if c := ref.GetCondition(); c != "" {
+			row.HasCondition = true
+			if !slices.Contains(row.Conditions, c) {
+				row.Conditions = append(row.Conditions, c)
+			}
+		}
+	}
+	return row
+}
@@ -0,0 +1,119 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type StoreRow struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	CreatedAt *time.Time `json:"created_at"`
+	UpdatedAt *time.Time `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	IsCurrent bool       `json:"is_current"`
+}
+
+// storePageSize is the page size used when paging through ListStores.
+const storePageSize = 100
+
+func tableOpenFGAStore(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "openfga_store",
+		Description: "OpenFGA stores visible to the connection",
+		List: &plugin.ListConfig{
+			Hydrate: listStores,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "name", Require: plugin.Optional},
+			},
+		},
+		Get: &plugin.GetConfig{
+			Hydrate:    getStore,
+			KeyColumns: plugin.SingleColumn("id"),
+			IgnoreConfig: &plugin.IgnoreConfig{
+				ShouldIgnoreErrorFunc: isNotFoundError,
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "id", Type: proto.ColumnType_STRING, Description: "Store ID (ULID)"},
+			{Name: "name", Type: proto.ColumnType_STRING, Description: "Store name"},
+			{Name: "created_at", Type: proto.ColumnType_TIMESTAMP, Description: "Time the store was created"},
+			{Name: "updated_at", Type: proto.ColumnType_TIMESTAMP, Description: "Time the store was last updated"},
+			{Name: "deleted_at", Type: proto.ColumnType_TIMESTAMP, Description: "Time the store was deleted, NULL for active stores"},
+			{Name: "is_current", Type: proto.ColumnType_BOOL, Description: "Whether the connection queries this store: its store_id, one of its store_ids, or any active store for store_ids = [\"*\"]", Transform: transform.FromField("IsCurrent")},
+		},
+	}
+}
+
+func listStores(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getConnectionClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	name := d.EqualsQualString("name")
+
+	var continuationToken string
+	for {
+		res, err := client.ListStores(ctx, &openfgav1.ListStoresRequest{
+			PageSize:          wrapperspb.Int32(storePageSize),
+			ContinuationToken: continuationToken,
+			Name:              name,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListStores: %w", err)
+		}
+
+		for _, store := range res.GetStores() {
+			d.StreamListItem(ctx, newStoreRow(client, store.GetId(), store.GetName(), store.GetCreatedAt(), store.GetUpdatedAt(), store.GetDeletedAt()))
+			if d.RowsRemaining(ctx) == 0 {
+				return nil, nil
+			}
+		}
+
+		continuationToken = res.GetContinuationToken()
+		if continuationToken == "" {
+			break
+		}
+	}
+	return nil, nil
+}
+
+func getStore(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getConnectionClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	id := d.EqualsQualString("id")
+	if id == "" {
+		return nil, nil
+	}
+
+	res, err := client.GetStore(ctx, &openfgav1.GetStoreRequest{StoreId: id})
+	if err != nil {
+		return nil, fmt.Errorf("GetStore: %w", err)
+	}
+	return newStoreRow(client, res.GetId(), res.GetName(), res.GetCreatedAt(), res.GetUpdatedAt(), res.GetDeletedAt()), nil
+}
+
+// newStoreRow builds a row from the fields shared by Store and GetStoreResponse.
+func newStoreRow(client *Client, id, name string, createdAt, updatedAt, deletedAt *timestamppb.Timestamp) StoreRow {
+	row := StoreRow{
+		ID:        id,
+		Name:      name,
+		CreatedAt: timeValue(createdAt),
+		UpdatedAt: timeValue(updatedAt),
+		DeletedAt: timeValue(deletedAt),
+	}
+	row.IsCurrent = client.servesStore(id, row.DeletedAt)
+	return row
+}
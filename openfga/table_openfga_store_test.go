@@ -0,0 +1,79 @@
+package openfga
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// storeCatalogGetServer answers GetStore like OpenFGA does, with store_id_not_found for unknown stores.
+type storeCatalogGetServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+	stores map[string]*openfgav1.GetStoreResponse
+}
+
+func (s *storeCatalogGetServer) GetStore(_ context.Context, req *openfgav1.GetStoreRequest) (*openfgav1.GetStoreResponse, error) {
+	if store, ok := s.stores[req.GetStoreId()]; ok {
+		return store, nil
+	}
+	return nil, status.Error(codes.Code(openfgav1.NotFoundErrorCode_store_id_not_found), "store_id_not_found")
+}
+
+func TestGetStore(t *testing.T) {
+	deleted := timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	addr := startTestServer(t, &storeCatalogGetServer{stores: map[string]*openfgav1.GetStoreResponse{
+		"a": {Id: "a", Name: "prod"},
+		"b": {Id: "b", Name: "staging"},
+		"c": {Id: "c", Name: "old", DeletedAt: deleted},
+	}})
+	t.Cleanup(clearClientCache)
+
+	connection := &plugin.Connection{
+		Name:   "get_store_test",
+		Config: &Config{Endpoint: addr, StoreIds: []string{"a", "c"}},
+	}
+
+	tests := []struct {
+		id          string
+		wantName    string
+		wantCurrent bool
+		wantMissing bool
+	}{
+		{id: "a", wantName: "prod", wantCurrent: true},
+		{id: "b", wantName: "staging", wantCurrent: false},
+		{id: "c", wantName: "old", wantCurrent: true},
+		{id: "x", wantMissing: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			ctx := testContext()
+			d := &plugin.QueryData{
+				Connection:  connection,
+				EqualsQuals: plugin.KeyColumnEqualsQualMap{"id": stringQual(tt.id)},
+			}
+
+			got, err := getStore(ctx, d, nil)
+			if tt.wantMissing {
+				if err == nil || !isNotFoundError(ctx, d, nil, err) {
+					t.Fatalf("getStore(%s) = (%v, %v), want an error the Get config ignores", tt.id, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getStore(%s) failed: %v", tt.id, err)
+			}
+
+			row := got.(StoreRow)
+			if row.ID != tt.id || row.Name != tt.wantName || row.IsCurrent != tt.wantCurrent {
+				t.Fatalf("getStore(%s) = %+v, want name %q is_current %v", tt.id, row, tt.wantName, tt.wantCurrent)
+			}
+		})
+	}
+}
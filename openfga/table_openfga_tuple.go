@@ -0,0 +1,228 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type TupleRow struct {
+	Object           string           `json:"object"`
+	ObjectType       string           `json:"object_type"`
+	ObjectID         string           `json:"object_id"`
+	Relation         string           `json:"relation"`
+	User             string           `json:"user"`
+	UserType         string           `json:"user_type"`
+	UserID           string           `json:"user_id"`
+	UserRelation     string           `json:"user_relation"`
+	ConditionName    string           `json:"condition_name"`
+	ConditionContext *structpb.Struct `json:"condition_context"`
+	Timestamp        *time.Time       `json:"timestamp"`
+	Consistency      string           `json:"consistency"`
+}
+
+// tuplePageSize is the page size used when paging through Read; 100 is the server maximum.
+const tuplePageSize = 100
+
+func tableOpenFGATuple(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_tuple",
+		Description:       "Relationship tuples stored in the connection's store, as returned by Read",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listTuples,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: "object", Require: plugin.Optional},
+				{Name: objectTypeCol, Require: plugin.Optional},
+				{Name: objectIDCol, Require: plugin.Optional},
+				{Name: relationCol, Require: plugin.Optional},
+				{Name: "user", Require: plugin.Optional},
+				{Name: "user_type", Require: plugin.Optional},
+				{Name: "user_id", Require: plugin.Optional},
+				{Name: "user_relation", Require: plugin.Optional},
+				{Name: consistencyCol, Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: "object", Type: proto.ColumnType_STRING, Description: "Object of the tuple, e.g. 'document:roadmap'"},
+			{Name: objectTypeCol, Type: proto.ColumnType_STRING, Description: "Type of the object, e.g. 'document'"},
+			{Name: objectIDCol, Type: proto.ColumnType_STRING, Description: "ID of the object, e.g. 'roadmap'"},
+			{Name: relationCol, Type: proto.ColumnType_STRING, Description: "Relation of the tuple, e.g. 'viewer'"},
+			{Name: "user", Type: proto.ColumnType_STRING, Description: "User of the tuple: 'user:anne', a userset such as 'group:eng#member' or a wildcard such as 'user:*'"},
+			{Name: "user_type", Type: proto.ColumnType_STRING, Description: "Type of the user, e.g. 'group'"},
+			{Name: "user_id", Type: proto.ColumnType_STRING, Description: "ID of the user, '*' for a wildcard"},
+			{Name: "user_relation", Type: proto.ColumnType_STRING, Description: "Relation of a userset user, e.g. 'member' for 'group:eng#member'"},
+			{Name: "condition_name", Type: proto.ColumnType_STRING, Description: "Name of the condition the tuple was written with"},
+			{Name: "condition_context", Type: proto.ColumnType_JSON, Description: "Context stored with the tuple condition", Transform: transform.FromField("ConditionContext").Transform(protoToJSON)},
+			{Name: "timestamp", Type: proto.ColumnType_TIMESTAMP, Description: "Time the tuple was written"},
+			{Name: consistencyCol, Type: proto.ColumnType_STRING, Description: "Consistency preference of the request: minimize_latency, higher_consistency or unspecified; defaults to the connection's consistency"},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listTuples(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	q := map[string]string{}
+	for _, column := range []string{"object", objectTypeCol, objectIDCol, relationCol, "user", "user_type", "user_id", "user_relation"} {
+		q[column] = filterQualString(d, column)
+	}
+
+	consistency, consistencyText, err := consistencyQual(d, client)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := tupleFilter{
+		ObjectType:   q[objectTypeCol],
+		ObjectID:     q[objectIDCol],
+		Relation:     q[relationCol],
+		UserType:     q["user_type"],
+		UserID:       q["user_id"],
+		UserRelation: q["user_relation"],
+	}
+	if q["object"] != "" {
+		filter.ObjectType, filter.ObjectID = splitObject(q["object"])
+	}
+	if q["user"] != "" {
+		filter.UserType, filter.UserID, filter.UserRelation = splitUser(q["user"])
+		filter.exactUser = true
+	}
+
+	err = visitTuples(ctx, client, filter.readTupleKey(), consistency, func(t *openfgav1.Tuple) bool {
+		if !filter.matches(t.GetKey()) {
+			return true
+		}
+		row := newTupleRow(t)
+		row.Consistency = consistencyText
+		d.StreamListItem(ctx, row)
+		return d.RowsRemaining(ctx) != 0
+	})
+	return nil, err
+}
+
+// tupleFilter is the set of equality quals on a stored tuple. Empty fields match anything.
+type tupleFilter struct {
+	ObjectType   string
+	ObjectID     string
+	Relation     string
+	UserType     string
+	UserID       string
+	UserRelation string
+
+	// exactUser means UserRelation is known, empty included: "group:eng" and "group:eng#member" are different users,
+	// so user_type and user_id alone cannot be sent to Read.
+	exactUser bool
+}
+
+// readTupleKey returns the narrowest Read filter the quals allow; see newReadTupleKey.
+func (f tupleFilter) readTupleKey() *openfgav1.ReadRequestTupleKey {
+	var object, user string
+	if f.ObjectType != "" {
+		// object_id 가 없으면 "type:" 형태
+		object = f.ObjectType + ":" + f.ObjectID
+	}
+	if f.UserType != "" && f.UserID != "" && (f.exactUser || f.UserRelation != "") {
+		user = joinUser(f.UserType, f.UserID, f.UserRelation)
+	}
+	return newReadTupleKey(object, f.Relation, user)
+}
+
+// matches applies the quals Read could not take. Rows must be filtered here rather than left to Postgres,
+// otherwise a LIMIT pushed down to the plugin would count rows that Postgres later discards.
+func (f tupleFilter) matches(key *openfgav1.TupleKey) bool {
+	objectType, objectID := splitObject(key.GetObject())
+	userType, userID, userRelation := splitUser(key.GetUser())
+
+	switch {
+	case f.ObjectType != "" && objectType != f.ObjectType,
+		f.ObjectID != "" && objectID != f.ObjectID,
+		f.Relation != "" && key.GetRelation() != f.Relation,
+		f.UserType != "" && userType != f.UserType,
+		f.UserID != "" && userID != f.UserID,
+		(f.exactUser || f.UserRelation != "") && userRelation != f.UserRelation:
+		return false
+	default:
+		return true
+	}
+}
+
+// newReadTupleKey maps the filters onto the combinations Read accepts:
+//
+//   - nothing: every tuple in the store
+//   - object "type:id", optionally with relation and/or user
+//   - object "type:" (type only) with user, optionally with relation
+//
+// Filters that fit none of these read the whole store and are applied by tupleFilter.matches.
+func newReadTupleKey(object, relation, user string) *openfgav1.ReadRequestTupleKey {
+	if object == "" {
+		return nil
+	}
+	if _, id := splitObject(object); id == "" && user == "" {
+		return nil
+	}
+	return &openfgav1.ReadRequestTupleKey{
+		Object:   object,
+		Relation: relation,
+		User:     user,
+	}
+}
+
+// visitTuples pages through Read with tupleKey and calls visit for every tuple until visit returns false.
+func visitTuples(ctx context.Context, client *Client, tupleKey *openfgav1.ReadRequestTupleKey, consistency openfgav1.ConsistencyPreference, visit func(*openfgav1.Tuple) bool) error {
+	var continuationToken string
+	for {
+		res, err := client.Read(ctx, &openfgav1.ReadRequest{
+			StoreId:           client.storeID,
+			TupleKey:          tupleKey,
+			PageSize:          wrapperspb.Int32(tuplePageSize),
+			ContinuationToken: continuationToken,
+			Consistency:       consistency,
+		})
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+
+		for _, t := range res.GetTuples() {
+			if !visit(t) {
+				return nil
+			}
+		}
+
+		continuationToken = res.GetContinuationToken()
+		if continuationToken == "" {
+			return nil
+		}
+	}
+}
+
+func newTupleRow(t *openfgav1.Tuple) TupleRow {
+	key := t.GetKey()
+	objectType, objectID := splitObject(key.GetObject())
+	userType, userID, userRelation := splitUser(key.GetUser())
+
+	return TupleRow{
+		Object:           key.GetObject(),
+		ObjectType:       objectType,
+		ObjectID:         objectID,
+		Relation:         key.GetRelation(),
+		User:             key.GetUser(),
+		UserType:         userType,
+		UserID:           userID,
+		UserRelation:     userRelation,
+		ConditionName:    key.GetCondition().GetName(),
+		ConditionContext: key.GetCondition().GetContext(),
+		Timestamp:        timeValue(t.GetTimestamp()),
+	}
+}
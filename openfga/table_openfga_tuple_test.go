@@ -0,0 +1,109 @@
+package openfga
+
+import (
+	"testing"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewReadTupleKey(t *testing.T) {
+	tests := []struct {
+		name                   string
+		object, relation, user string
+		want                   *openfgav1.ReadRequestTupleKey
+	}{
+		{"no filter", "", "", "", nil},
+		{"relation only", "", "viewer", "", nil},
+		{"user only", "", "", "user:anne", nil},
+		{"object", "document:roadmap", "", "", &openfgav1.ReadRequestTupleKey{Object: "document:roadmap"}},
+		{"object and relation", "document:roadmap", "viewer", "", &openfgav1.ReadRequestTupleKey{Object: "document:roadmap", Relation: "viewer"}},
+		{"object and userset", "document:roadmap", "", "group:eng#member", &openfgav1.ReadRequestTupleKey{Object: "document:roadmap", User: "group:eng#member"}},
+		{"type without user", "document:", "viewer", "", nil},
+		{"type with user", "document:", "viewer", "user:*", &openfgav1.ReadRequestTupleKey{Object: "document:", Relation: "viewer", User: "user:*"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newReadTupleKey(tt.object, tt.relation, tt.user)
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("newReadTupleKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTupleFilter(t *testing.T) {
+	direct := &openfgav1.TupleKey{Object: "document:roadmap", Relation: "viewer", User: "group:eng"}
+	userset := &openfgav1.TupleKey{Object: "document:roadmap", Relation: "viewer", User: "group:eng#member"}
+	other := &openfgav1.TupleKey{Object: "folder:plans", Relation: "viewer", User: "user:anne"}
+
+	tests := []struct {
+		name       string
+		filter     tupleFilter
+		wantKey    *openfgav1.ReadRequestTupleKey
+		wantMatch  []*openfgav1.TupleKey
+		wantReject []*openfgav1.TupleKey
+	}{
+		{
+			name:       "object",
+			filter:     tupleFilter{ObjectType: "document", ObjectID: "roadmap", Relation: "viewer"},
+			wantKey:    &openfgav1.ReadRequestTupleKey{Object: "document:roadmap", Relation: "viewer"},
+			wantMatch:  []*openfgav1.TupleKey{direct, userset},
+			wantReject: []*openfgav1.TupleKey{other},
+		},
+		{
+			name:       "object type only is filtered client-side",
+			filter:     tupleFilter{ObjectType: "document"},
+			wantKey:    nil,
+			wantMatch:  []*openfgav1.TupleKey{direct, userset},
+			wantReject: []*openfgav1.TupleKey{other},
+		},
+		{
+			name:       "type-only object with an exact user",
+			filter:     tupleFilter{ObjectType: "document", UserType: "group", UserID: "eng", UserRelation: "member"},
+			wantKey:    &openfgav1.ReadRequestTupleKey{Object: "document:", User: "group:eng#member"},
+			wantMatch:  []*openfgav1.TupleKey{userset},
+			wantReject: []*openfgav1.TupleKey{direct, other},
+		},
+		{
+			name:       "user without relation is not sent to Read",
+			filter:     tupleFilter{ObjectType: "document", UserType: "group", UserID: "eng"},
+			wantKey:    nil,
+			wantMatch:  []*openfgav1.TupleKey{direct, userset},
+			wantReject: []*openfgav1.TupleKey{other},
+		},
+		{
+			name:       "exact direct user",
+			filter:     tupleFilter{ObjectType: "document", UserType: "group", UserID: "eng", exactUser: true},
+			wantKey:    &openfgav1.ReadRequestTupleKey{Object: "document:", User: "group:eng"},
+			wantMatch:  []*openfgav1.TupleKey{direct},
+			wantReject: []*openfgav1.TupleKey{userset, other},
+		},
+		{
+			name:       "subject only",
+			filter:     tupleFilter{UserType: "user", UserID: "anne", Relation: "viewer"},
+			wantKey:    nil,
+			wantMatch:  []*openfgav1.TupleKey{other},
+			wantReject: []*openfgav1.TupleKey{direct, userset},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.readTupleKey(); !proto.Equal(got, tt.wantKey) {
+				t.Errorf("readTupleKey() = %v, want %v", got, tt.wantKey)
+			}
+			for _, key := range tt.wantMatch {
+				if !tt.filter.matches(key) {
+					t.Errorf("matches(%v) = false, want true", key)
+				}
+			}
+			for _, key := range tt.wantReject {
+				if tt.filter.matches(key) {
+					t.Errorf("matches(%v) = true, want false", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package openfga
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+type TypeDefinitionRow struct {
+	AuthorizationModelID string                    `json:"authorization_model_id"`
+	Type                 string                    `json:"type"`
+	RelationCount        int                       `json:"relation_count"`
+	Relations            []string                  `json:"relations"`
+	Module               string                    `json:"module"`
+	Definition           *openfgav1.TypeDefinition `json:"definition"`
+}
+
+func tableOpenFGATypeDefinition(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:              "openfga_type_definition",
+		Description:       "One row per type definition of every authorization model in the store",
+		GetMatrixItemFunc: storeMatrix,
+		List: &plugin.ListConfig{
+			Hydrate: listTypeDefinitions,
+			KeyColumns: []*plugin.KeyColumn{
+				{Name: authorizationModelIDCol, Require: plugin.Optional},
+				{Name: "type", Require: plugin.Optional},
+			},
+		},
+		Columns: []*plugin.Column{
+			{Name: authorizationModelIDCol, Type: proto.ColumnType_STRING, Description: "Authorization model the type belongs to"},
+			{Name: "type", Type: proto.ColumnType_STRING, Description: "Type name, e.g. 'document'"},
+			{Name: "relation_count", Type: proto.ColumnType_INT, Description: "Number of relations defined on the type", Transform: transform.FromField("RelationCount")},
+			{Name: "relations", Type: proto.ColumnType_JSON, Description: "Names of the relations defined on the type, sorted"},
+			{Name: "module", Type: proto.ColumnType_STRING, Description: "Module that defines the type in a modular model"},
+			{Name: "definition", Type: proto.ColumnType_JSON, Description: "Type definition in the OpenFGA API JSON format", Transform: transform.FromField("Definition").Transform(protoToJSON)},
+			storeIDColumn(),
+		},
+	}
+}
+
+func listTypeDefinitions(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (any, error) {
+	client, err := getClient(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	modelID := filterQualString(d, authorizationModelIDCol)
+	typeName := filterQualString(d, "type")
+
+	err = visitAuthorizationModels(ctx, client, modelID, func(model *openfgav1.AuthorizationModel) bool {
+		for _, typeDef := range model.GetTypeDefinitions() {
+			if typeName != "" && typeDef.GetType() != typeName {
+				continue
+			}
+
+			relations := slices.Sorted(maps.Keys(typeDef.GetRelations()))
+			d.StreamListItem(ctx, TypeDefinitionRow{
+				AuthorizationModelID: model.GetId(),
+				Type:                 typeDef.GetType(),
+				RelationCount:        len(relations),
+				Relations:            relations,
+				Module:               typeDef.GetMetadata().GetModule(),
+				Definition:           typeDef,
+			})
+			if d.RowsRemaining(ctx) == 0 {
+				return false
+			}
+		}
+		return true
+	})
+	return nil, err
+}
@@ -0,0 +1,90 @@
+package openfga
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newTransportCredentials builds the gRPC transport credentials for the connection.
+// Without use_tls the connection is plaintext and any TLS option is rejected rather than silently ignored.
+func newTransportCredentials(cfg Config) (credentials.TransportCredentials, error) {
+	if !useTLS(cfg) {
+		if hasTLSOptions(cfg) {
+			return nil, fmt.Errorf("ca_cert_path, client_cert_path, client_key_path, tls_server_name and insecure_skip_verify require use_tls = true")
+		}
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := newTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// tlsMode describes the transport of the connection: plaintext, tls, or mtls when a client certificate is configured.
+func tlsMode(cfg Config) string {
+	switch {
+	case !useTLS(cfg):
+		return "plaintext"
+	case stringValue(cfg.ClientCertPath) != "":
+		return "mtls"
+	default:
+		return "tls"
+	}
+}
+
+func useTLS(cfg Config) bool {
+	return cfg.UseTLS != nil && *cfg.UseTLS
+}
+
+func hasTLSOptions(cfg Config) bool {
+	return stringValue(cfg.CACertPath) != "" ||
+		stringValue(cfg.ClientCertPath) != "" ||
+		stringValue(cfg.ClientKeyPath) != "" ||
+		stringValue(cfg.TLSServerName) != "" ||
+		(cfg.InsecureSkipVerify != nil && *cfg.InsecureSkipVerify)
+}
+
+// newTLSConfig loads the CA bundle and client certificate referenced by the connection config.
+// An empty ca_cert_path falls back to the system roots.
+func newTLSConfig(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         stringValue(cfg.TLSServerName),
+		InsecureSkipVerify: cfg.InsecureSkipVerify != nil && *cfg.InsecureSkipVerify,
+	}
+
+	if caPath := stringValue(cfg.CACertPath); caPath != "" {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_cert_path %q: %w", caPath, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_cert_path %q does not contain any PEM encoded certificate", caPath)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	certPath := stringValue(cfg.ClientCertPath)
+	keyPath := stringValue(cfg.ClientKeyPath)
+	switch {
+	case certPath != "" && keyPath != "":
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate %q / key %q: %w", certPath, keyPath, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	case certPath != "" || keyPath != "":
+		return nil, fmt.Errorf("client_cert_path and client_key_path must be set together")
+	}
+
+	return tlsConfig, nil
+}
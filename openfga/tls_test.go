@@ -0,0 +1,257 @@
+package openfga
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// testPKI is a throwaway CA with a server certificate for "openfga.test" and a client certificate.
+type testPKI struct {
+	caPath     string
+	certPath   string
+	keyPath    string
+	serverCert tls.Certificate
+	caPool     *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := func(serial int64, template *x509.Certificate) (certPEM, keyPEM []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template.SerialNumber = big.NewInt(serial)
+		template.NotBefore = time.Now().Add(-time.Hour)
+		template.NotAfter = time.Now().Add(time.Hour)
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	serverCertPEM, serverKeyPEM := issue(2, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "openfga.test"},
+		DNSNames:    []string{"openfga.test"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+	serverCert, err := tls.X509KeyPair(serverCertPEM, serverKeyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientCertPEM, clientKeyPEM := issue(3, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "steampipe"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	pki := &testPKI{
+		caPath:     filepath.Join(dir, "ca.pem"),
+		certPath:   filepath.Join(dir, "client.pem"),
+		keyPath:    filepath.Join(dir, "client-key.pem"),
+		serverCert: serverCert,
+		caPool:     x509.NewCertPool(),
+	}
+	pki.caPool.AddCert(caCert)
+
+	writeFile := func(path string, data []byte) {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(pki.caPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+	writeFile(pki.certPath, clientCertPEM)
+	writeFile(pki.keyPath, clientKeyPEM)
+	return pki
+}
+
+// startTLSServer starts the fake OpenFGA server with TLS, optionally requiring a client certificate.
+func startTLSServer(t *testing.T, pki *testPKI, requireClientCert bool, opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{pki.serverCert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if requireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.ClientCAs = pki.caPool
+	}
+	opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	return startTestServer(t, &fakeOpenFGAServer{}, opts...)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func testCheckRequest() *openfgav1.CheckRequest {
+	return &openfgav1.CheckRequest{
+		StoreId: "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		TupleKey: &openfgav1.CheckRequestTupleKey{
+			Object:   "doc:1",
+			User:     "user:alice",
+			Relation: "viewer",
+		},
+	}
+}
+
+// checkWithConfig issues a Check through a client built from cfg and returns the RPC error.
+func checkWithConfig(t *testing.T, cfg Config) error {
+	t.Helper()
+
+	client, err := NewClient(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = client.Check(ctx, testCheckRequest())
+	return err
+}
+
+func TestNewClient_TLS(t *testing.T) {
+	pki := newTestPKI(t)
+	addr := startTLSServer(t, pki, false)
+
+	t.Run("CA bundle with server name override", func(t *testing.T) {
+		err := checkWithConfig(t, Config{
+			Endpoint:      addr,
+			UseTLS:        ptr(true),
+			CACertPath:    &pki.caPath,
+			TLSServerName: ptr("openfga.test"),
+		})
+		if err != nil {
+			t.Fatalf("Check over TLS failed: %v", err)
+		}
+	})
+
+	t.Run("server name mismatch is rejected", func(t *testing.T) {
+		err := checkWithConfig(t, Config{
+			Endpoint:   addr,
+			UseTLS:     ptr(true),
+			CACertPath: &pki.caPath,
+		})
+		if err == nil {
+			t.Fatal("Expected a certificate verification error for 127.0.0.1")
+		}
+	})
+
+	t.Run("unknown authority is rejected", func(t *testing.T) {
+		err := checkWithConfig(t, Config{
+			Endpoint:      addr,
+			UseTLS:        ptr(true),
+			TLSServerName: ptr("openfga.test"),
+		})
+		if err == nil {
+			t.Fatal("Expected a certificate verification error without the CA bundle")
+		}
+	})
+
+	t.Run("insecure skip verify", func(t *testing.T) {
+		err := checkWithConfig(t, Config{
+			Endpoint:           addr,
+			UseTLS:             ptr(true),
+			InsecureSkipVerify: ptr(true),
+		})
+		if err != nil {
+			t.Fatalf("Check with insecure_skip_verify failed: %v", err)
+		}
+	})
+}
+
+func TestNewClient_MutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	addr := startTLSServer(t, pki, true)
+
+	cfg := Config{
+		Endpoint:      addr,
+		UseTLS:        ptr(true),
+		CACertPath:    &pki.caPath,
+		TLSServerName: ptr("openfga.test"),
+	}
+	if err := checkWithConfig(t, cfg); err == nil {
+		t.Fatal("Expected the server to reject a client without a certificate")
+	}
+
+	cfg.ClientCertPath = &pki.certPath
+	cfg.ClientKeyPath = &pki.keyPath
+	if err := checkWithConfig(t, cfg); err != nil {
+		t.Fatalf("Check over mTLS failed: %v", err)
+	}
+}
+
+func TestNewTransportCredentials_Errors(t *testing.T) {
+	pki := newTestPKI(t)
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{"unreadable CA", Config{UseTLS: ptr(true), CACertPath: &missing}, "failed to read ca_cert_path"},
+		{"CA without certificates", Config{UseTLS: ptr(true), CACertPath: &pki.keyPath}, "does not contain any PEM encoded certificate"},
+		{"unreadable client key", Config{UseTLS: ptr(true), ClientCertPath: &pki.certPath, ClientKeyPath: &missing}, "failed to load client certificate"},
+		{"client cert without key", Config{UseTLS: ptr(true), ClientCertPath: &pki.certPath}, "must be set together"},
+		{"TLS option without use_tls", Config{CACertPath: &pki.caPath}, "require use_tls = true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTransportCredentials(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("newTransportCredentials() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
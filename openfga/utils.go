@@ -1,6 +1,25 @@
 package openfga
 
-import "strings"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/quals"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
 
 func splitObject(obj string) (objectType, objectID string) {
 	parts := strings.SplitN(obj, ":", 2)
@@ -9,3 +28,196 @@ func splitObject(obj string) (objectType, objectID string) {
 	}
 	return obj, ""
 }
+
+// splitUser splits a tuple user such as "user:anne", "user:*" or "group:eng#member" into its parts.
+func splitUser(user string) (userType, userID, userRelation string) {
+	userType, userID = splitObject(user)
+	if i := strings.LastIndex(userID, "#"); i >= 0 {
+		userID, userRelation = userID[:i], userID[i+1:]
+	}
+	return userType, userID, userRelation
+}
+
+// joinUser is the inverse of splitUser.
+func joinUser(userType, userID, userRelation string) string {
+	user := userType + ":" + userID
+	if userRelation != "" {
+		user += "#" + userRelation
+	}
+	return user
+}
+
+func firstOrEmpty(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// qualValueStrings returns the string values of a qual value, expanding list values.
+func qualValueStrings(v *proto.QualValue) []string {
+	if v == nil {
+		return nil
+	}
+	if list := v.GetListValue(); list != nil {
+		values := make([]string, 0, len(list.GetValues()))
+		for _, item := range list.GetValues() {
+			if s := item.GetStringValue(); s != "" && !slices.Contains(values, s) {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	if s := v.GetStringValue(); s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+// singleQualString returns the equals qual of column, rejecting IN / = ANY(...) lists.
+// Unlike QueryData.EqualsQualString it does not panic on list values.
+func singleQualString(d *plugin.QueryData, column string) (string, error) {
+	values := qualValueStrings(d.EqualsQuals[column])
+	if len(values) > 1 {
+		return "", fmt.Errorf("%s does not accept a list of values", column)
+	}
+	return firstOrEmpty(values), nil
+}
+
+// filterQualString returns the equals qual of a column that only filters rows, or "" to scan unfiltered when the qual
+// is a list. Postgres rechecks every qual on the rows, so the extra rows are dropped there.
+func filterQualString(d *plugin.QueryData, column string) string {
+	values := qualValueStrings(d.EqualsQuals[column])
+	if len(values) > 1 {
+		return ""
+	}
+	return firstOrEmpty(values)
+}
+
+// listQualStrings returns the values supplied for column through "=", IN or = ANY(...).
+//
+// When a single key column carries a list, the SDK splits the query into one List call per list element,
+// duplicates and empty strings included, and hands each call the element itself. To keep the values in one
+// batch, the call holding the first element re-expands the original qual from the query context and handles
+// all of them, and the other calls report skip. A qual left with no value matches no row and reports skip.
+func listQualStrings(d *plugin.QueryData, column string) (values []string, skip bool) {
+	v := d.EqualsQuals[column]
+	if v == nil {
+		return nil, false
+	}
+
+	if v.GetListValue() == nil && d.QueryContext != nil {
+		for _, q := range d.QueryContext.UnsafeQuals[column].GetQuals() {
+			list := q.GetValue().GetListValue()
+			if q.GetStringValue() != quals.QualOperatorEqual || list == nil {
+				continue
+			}
+			// 값이 아닌 위치로 고른다: 같은 값이 여러 번 나와도 리더는 하나다.
+			i := slices.Index(list.GetValues(), v)
+			if i < 0 {
+				continue
+			}
+			if i > 0 {
+				return nil, true
+			}
+			v = q.GetValue()
+			break
+		}
+	}
+
+	values = qualValueStrings(v)
+	return values, len(values) == 0
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// timeValue converts an optional protobuf timestamp, returning nil when it is unset or zero.
+func timeValue(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	if t.IsZero() || t.Unix() == 0 {
+		return nil
+	}
+	return &t
+}
+
+// isNotFoundError reports whether OpenFGA answered that the requested resource does not exist, so Get calls
+// return no row instead of failing. OpenFGA reports a missing store or model with its own error codes as the
+// gRPC status code rather than with NotFound.
+func isNotFoundError(_ context.Context, _ *plugin.QueryData, _ *plugin.HydrateData, err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound,
+		codes.Code(openfgav1.NotFoundErrorCode_store_id_not_found),
+		codes.Code(openfgav1.ErrorCode_authorization_model_not_found):
+		return true
+	}
+	return false
+}
+
+// crockfordBase32 is the ULID alphabet.
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidTime returns the creation time encoded in the first 10 characters of a ULID, or nil if id is not a ULID.
+func ulidTime(id string) *time.Time {
+	if len(id) != 26 {
+		return nil
+	}
+
+	var ms uint64
+	for _, c := range strings.ToUpper(id[:10]) {
+		v := strings.IndexRune(crockfordBase32, c)
+		if v < 0 {
+			return nil
+		}
+		ms = ms<<5 | uint64(v)
+	}
+	// 10 글자 = 50 bit 이지만 ULID timestamp 는 48 bit 이다.
+	if ms >= 1<<48 {
+		return nil
+	}
+
+	t := time.UnixMilli(int64(ms)).UTC()
+	return &t
+}
+
+// protoToJSON renders a protobuf message, or a slice of messages as a JSON array, using the proto field names,
+// matching the OpenFGA HTTP API.
+func protoToJSON(_ context.Context, d *transform.TransformData) (any, error) {
+	if v := reflect.ValueOf(d.Value); v.Kind() == reflect.Slice {
+		items := make([]json.RawMessage, 0, v.Len())
+		for i := range v.Len() {
+			msg, ok := v.Index(i).Interface().(protoreflect.ProtoMessage)
+			if !ok {
+				return nil, fmt.Errorf("protoToJSON: %T is not a protobuf message", v.Index(i).Interface())
+			}
+			b, err := marshalProto(msg)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, b)
+		}
+		return items, nil
+	}
+
+	msg, ok := d.Value.(protoreflect.ProtoMessage)
+	if !ok || msg == nil || !msg.ProtoReflect().IsValid() {
+		return nil, nil
+	}
+
+	b, err := marshalProto(msg)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(b), nil
+}
+
+func marshalProto(msg protoreflect.ProtoMessage) (json.RawMessage, error) {
+	return protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+}
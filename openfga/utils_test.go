@@ -1,7 +1,18 @@
 package openfga
 
 import (
+	"context"
+	"encoding/json"
+	"slices"
 	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestSplitObject(t *testing.T) {
@@ -28,3 +39,203 @@ func TestSplitObject(t *testing.T) {
 		})
 	}
 }
+
+func stringQual(s string) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: s}}
+}
+
+func jsonQual(s string) *proto.QualValue {
+	return &proto.QualValue{Value: &proto.QualValue_JsonbValue{JsonbValue: s}}
+}
+
+func listQual(values ...string) *proto.QualValue {
+	list := &proto.QualValueList{}
+	for _, v := range values {
+		list.Values = append(list.Values, stringQual(v))
+	}
+	return &proto.QualValue{Value: &proto.QualValue_ListValue{ListValue: list}}
+}
+
+func TestListQualStrings(t *testing.T) {
+	// the SDK hands each split call an element of this list, duplicates and empty strings included
+	list := listQual("alice", "alice", "bob", "", "charlie")
+	elements := list.GetListValue().GetValues()
+	unsafe := &plugin.QueryContext{UnsafeQuals: map[string]*proto.Quals{
+		"subject_id": {Quals: []*proto.Qual{{
+			FieldName: "subject_id",
+			Operator:  &proto.Qual_StringValue{StringValue: "="},
+			Value:     list,
+		}}},
+	}}
+
+	empty := listQual("", "")
+	emptyOnly := &plugin.QueryContext{UnsafeQuals: map[string]*proto.Quals{
+		"subject_id": {Quals: []*proto.Qual{{
+			FieldName: "subject_id",
+			Operator:  &proto.Qual_StringValue{StringValue: "="},
+			Value:     empty,
+		}}},
+	}}
+
+	tests := []struct {
+		name       string
+		qual       *proto.QualValue
+		ctx        *plugin.QueryContext
+		wantValues []string
+		wantSkip   bool
+	}{
+		{"missing", nil, nil, nil, false},
+		{"single value", stringQual("alice"), nil, []string{"alice"}, false},
+		{"empty value", stringQual(""), nil, nil, true},
+		{"list value", listQual("alice", "bob", "alice"), nil, []string{"alice", "bob"}, false},
+		{"split leader", elements[0], unsafe, []string{"alice", "bob", "charlie"}, false},
+		{"split duplicate of the leader", elements[1], unsafe, nil, true},
+		{"split follower", elements[2], unsafe, nil, true},
+		{"split empty element", elements[3], unsafe, nil, true},
+		{"split of empty strings only", empty.GetListValue().GetValues()[0], emptyOnly, nil, true},
+		{"unrelated value", stringQual("dave"), unsafe, []string{"dave"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &plugin.QueryData{
+				EqualsQuals:  plugin.KeyColumnEqualsQualMap{},
+				QueryContext: tt.ctx,
+			}
+			if tt.qual != nil {
+				d.EqualsQuals["subject_id"] = tt.qual
+			}
+
+			gotValues, gotSkip := listQualStrings(d, "subject_id")
+			if !slices.Equal(gotValues, tt.wantValues) || gotSkip != tt.wantSkip {
+				t.Fatalf("listQualStrings() = (%v, %v), want (%v, %v)", gotValues, gotSkip, tt.wantValues, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func TestSingleQualString(t *testing.T) {
+	d := &plugin.QueryData{EqualsQuals: plugin.KeyColumnEqualsQualMap{
+		"object_type":  stringQual("doc"),
+		"subject_type": listQual("user", "group"),
+	}}
+
+	if got, err := singleQualString(d, "object_type"); err != nil || got != "doc" {
+		t.Fatalf("singleQualString(object_type) = (%q, %v), want (\"doc\", nil)", got, err)
+	}
+	if _, err := singleQualString(d, "subject_type"); err == nil {
+		t.Fatal("singleQualString(subject_type) expected an error for a list value")
+	}
+}
+
+func TestUlidTime(t *testing.T) {
+	// 01ARZ3NDEK... encodes 1469922850259 ms (2016-07-30T23:54:10.259Z)
+	got := ulidTime("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	want := time.UnixMilli(1469922850259).UTC()
+	if got == nil || !got.Equal(want) {
+		t.Fatalf("ulidTime() = %v, want %v", got, want)
+	}
+
+	for _, id := range []string{"", "not-a-ulid", "81ARZ3NDEKTSV4RRFFQ69G5FAV", "0!ARZ3NDEKTSV4RRFFQ69G5FAV"} {
+		if got := ulidTime(id); got != nil {
+			t.Errorf("ulidTime(%q) = %v, want nil", id, got)
+		}
+	}
+}
+
+func TestSplitUser(t *testing.T) {
+	tests := []struct {
+		user                      string
+		wantType, wantID, wantRel string
+	}{
+		{"user:anne", "user", "anne", ""},
+		{"user:*", "user", "*", ""},
+		{"group:eng#member", "group", "eng", "member"},
+		{"team:a#b#admin", "team", "a#b", "admin"},
+		{"", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			gotType, gotID, gotRel := splitUser(tt.user)
+			if gotType != tt.wantType || gotID != tt.wantID || gotRel != tt.wantRel {
+				t.Errorf("splitUser(%q) = (%q, %q, %q), want (%q, %q, %q)", tt.user, gotType, gotID, gotRel, tt.wantType, tt.wantID, tt.wantRel)
+			}
+			if tt.user != "" {
+				if got := joinUser(gotType, gotID, gotRel); got != tt.user {
+					t.Errorf("joinUser() = %q, want %q", got, tt.user)
+				}
+			}
+		})
+	}
+}
+
+func TestProtoToJSON(t *testing.T) {
+	render := func(v any) string {
+		t.Helper()
+		got, err := protoToJSON(context.Background(), &transform.TransformData{Value: v})
+		if err != nil {
+			t.Fatalf("protoToJSON failed: %v", err)
+		}
+		if got == nil {
+			return "null"
+		}
+		b, err := json.Marshal(got)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		return string(b)
+	}
+
+	key := &openfgav1.TupleKey{Object: "document:roadmap", Relation: "viewer", User: "user:anne"}
+	want := `{"user":"user:anne","relation":"viewer","object":"document:roadmap"}`
+
+	if got := render(key); got != want {
+		t.Errorf("message = %s, want %s", got, want)
+	}
+	if got := render([]*openfgav1.TupleKey{key}); got != "["+want+"]" {
+		t.Errorf("slice = %s, want [%s]", got, want)
+	}
+	if got := render([]*openfgav1.TupleKey{}); got != "[]" {
+		t.Errorf("empty slice = %s, want []", got)
+	}
+	if got := render((*openfgav1.TupleKey)(nil)); got != "null" {
+		t.Errorf("nil message = %s, want null", got)
+	}
+}
+
+func TestIsNotFoundError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"grpc not found", status.Error(codes.NotFound, "not found"), true},
+		{"store not found", status.Error(codes.Code(openfgav1.NotFoundErrorCode_store_id_not_found), "store_id_not_found"), true},
+		{"model not found", status.Error(codes.Code(openfgav1.ErrorCode_authorization_model_not_found), "authorization_model_not_found"), true},
+		{"other validation error", status.Error(codes.Code(openfgav1.ErrorCode_invalid_continuation_token), "invalid_continuation_token"), false},
+		{"unavailable", status.Error(codes.Unavailable, "unavailable"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNotFoundError(context.Background(), nil, nil, tt.err); got != tt.want {
+				t.Fatalf("isNotFoundError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterQualString(t *testing.T) {
+	d := &plugin.QueryData{EqualsQuals: plugin.KeyColumnEqualsQualMap{
+		"type":     stringQual("doc"),
+		"relation": listQual("viewer", "editor"),
+		"user":     listQual("user:anne", "user:anne"),
+	}}
+
+	for column, want := range map[string]string{"type": "doc", "relation": "", "user": "user:anne", "object": ""} {
+		if got := filterQualString(d, column); got != want {
+			t.Errorf("filterQualString(%s) = %q, want %q", column, got, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package openfga
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	openfgainternal "github.com/carped99/steampipe-plugin-openfga/internal/openfga"
+)
+
+// validationTimeout bounds the checks of validate_connection, which otherwise wait on an unreachable endpoint
+// until Postgres cancels the query.
+const validationTimeout = 10 * time.Second
+
+// validate checks that the server is serving and that the configured stores exist, are not deleted and have
+// the pinned model, or any model when none is pinned. Stores found through store_ids = ["*"] are not checked.
+func (c *Client) validate(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, validationTimeout)
+	defer cancel()
+
+	if err := openfgainternal.ValidateConnection(ctx, c.conn); err != nil {
+		return fmt.Errorf("validate_connection: health check failed: %w", err)
+	}
+
+	storeIDs := c.storeIDs
+	if slices.Equal(storeIDs, []string{allStores}) {
+		storeIDs = nil
+	} else if len(storeIDs) == 0 && c.storeID != "" {
+		storeIDs = []string{c.storeID}
+	}
+
+	for _, storeID := range storeIDs {
+		if err := openfgainternal.ValidateStore(ctx, c, storeID, c.modelID); err != nil {
+			return fmt.Errorf("validate_connection: %w", err)
+		}
+	}
+	return nil
+}
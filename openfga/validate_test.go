@@ -0,0 +1,124 @@
+package openfga
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	openfgav1 "github.com/carped99/steampipe-plugin-openfga/internal/openfga/gen/openfga/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// storeCatalogServer knows the stores "live" (with model "m1"), "empty" (no models) and "gone" (deleted).
+type storeCatalogServer struct {
+	openfgav1.UnimplementedOpenFGAServiceServer
+}
+
+func (s *storeCatalogServer) GetStore(_ context.Context, req *openfgav1.GetStoreRequest) (*openfgav1.GetStoreResponse, error) {
+	switch req.GetStoreId() {
+	case "live", "empty":
+		return &openfgav1.GetStoreResponse{Id: req.GetStoreId(), Name: req.GetStoreId()}, nil
+	case "gone":
+		return &openfgav1.GetStoreResponse{Id: "gone", Name: "gone", DeletedAt: timestamppb.New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))}, nil
+	}
+	return nil, status.Error(codes.NotFound, "store not found")
+}
+
+func (s *storeCatalogServer) ReadAuthorizationModels(_ context.Context, req *openfgav1.ReadAuthorizationModelsRequest) (*openfgav1.ReadAuthorizationModelsResponse, error) {
+	if req.GetStoreId() != "live" {
+		return &openfgav1.ReadAuthorizationModelsResponse{}, nil
+	}
+	return &openfgav1.ReadAuthorizationModelsResponse{AuthorizationModels: []*openfgav1.AuthorizationModel{{Id: "m1"}}}, nil
+}
+
+func (s *storeCatalogServer) ReadAuthorizationModel(_ context.Context, req *openfgav1.ReadAuthorizationModelRequest) (*openfgav1.ReadAuthorizationModelResponse, error) {
+	if req.GetStoreId() == "live" && req.GetId() == "m1" {
+		return &openfgav1.ReadAuthorizationModelResponse{AuthorizationModel: &openfgav1.AuthorizationModel{Id: "m1"}}, nil
+	}
+	return nil, status.Error(codes.NotFound, "authorization model not found")
+}
+
+// startHealthTestServer serves srv and the gRPC health service reporting the given status.
+func startHealthTestServer(t *testing.T, srv openfgav1.OpenFGAServiceServer, serving grpc_health_v1.HealthCheckResponse_ServingStatus) string {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", serving)
+
+	server := grpc.NewServer()
+	openfgav1.RegisterOpenFGAServiceServer(server, srv)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestValidate(t *testing.T) {
+	addr := startHealthTestServer(t, &storeCatalogServer{}, grpc_health_v1.HealthCheckResponse_SERVING)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr string
+	}{
+		{name: "store with latest model", cfg: Config{StoreId: ptr("live")}},
+		{name: "store with pinned model", cfg: Config{StoreId: ptr("live"), AuthorizationModelId: ptr("m1")}},
+		{name: "every active store", cfg: Config{StoreIds: []string{allStores}}},
+		{name: "unknown store", cfg: Config{StoreId: ptr("typo")}, wantErr: "Store not found"},
+		{name: "deleted store", cfg: Config{StoreIds: []string{"live", "gone"}}, wantErr: "Store is deleted"},
+		{name: "store without models", cfg: Config{StoreId: ptr("empty")}, wantErr: "no OpenFGA AuthorizationModels"},
+		{name: "unknown model", cfg: Config{StoreId: ptr("live"), AuthorizationModelId: ptr("m2")}, wantErr: "AuthorizationModel not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Endpoint = addr
+			client, err := NewClient(context.Background(), tt.cfg)
+			if err != nil {
+				t.Fatalf("NewClient failed: %v", err)
+			}
+			defer client.Close()
+
+			err = client.validate(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate failed: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) || !strings.HasPrefix(err.Error(), "validate_connection: ") {
+				t.Fatalf("validate = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("not serving", func(t *testing.T) {
+		client, err := NewClient(context.Background(), Config{
+			Endpoint: startHealthTestServer(t, &storeCatalogServer{}, grpc_health_v1.HealthCheckResponse_NOT_SERVING),
+			StoreId:  ptr("live"),
+		})
+		if err != nil {
+			t.Fatalf("NewClient failed: %v", err)
+		}
+		defer client.Close()
+
+		if err := client.validate(context.Background()); err == nil || !strings.Contains(err.Error(), "health check failed") {
+			t.Fatalf("validate = %v, want a health check error", err)
+		}
+	})
+}